@@ -17,26 +17,6 @@ var (
 		Usage: "Overrides the organization of the images",
 		Value: "grafana",
 	}
-	AlpineImageFlag = &cli.StringFlag{
-		Name:  "alpine-base",
-		Usage: "The alpine image to use as the base image when building the Alpine version of the Grafana docker image",
-		Value: "alpine:latest",
-	}
-	UbuntuImageFlag = &cli.StringFlag{
-		Name:  "ubuntu-base",
-		Usage: "The Ubuntu image to use as the base image when building the Ubuntu version of the Grafana docker image",
-		Value: "ubuntu:latest",
-	}
-	TagFormatFlag = &cli.StringFlag{
-		Name:  "tag-format",
-		Usage: "Provide a go template for formatting the docker tag(s) for images with an Alpine base",
-		Value: docker.DefaultTagFormat,
-	}
-	UbuntuTagFormatFlag = &cli.StringFlag{
-		Name:  "ubuntu-tag-format",
-		Usage: "Provide a go template for formatting the docker tag(s) for images with a ubuntu base",
-		Value: docker.DefaultUbuntuTagFormat,
-	}
 	BoringTagFormatFlag = &cli.StringFlag{
 		Name:  "boring-tag-format",
 		Usage: "Provide a go template for formatting the docker tag(s) for the boringcrypto build of Grafana Enterprise",
@@ -45,9 +25,46 @@ var (
 
 	DockerRegistry  = pipeline.NewStringFlagArgument(DockerRegistryFlag)
 	DockerOrg       = pipeline.NewStringFlagArgument(DockerOrgFlag)
-	AlpineImage     = pipeline.NewStringFlagArgument(AlpineImageFlag)
-	UbuntuImage     = pipeline.NewStringFlagArgument(UbuntuImageFlag)
-	TagFormat       = pipeline.NewStringFlagArgument(TagFormatFlag)
-	UbuntuTagFormat = pipeline.NewStringFlagArgument(UbuntuTagFormatFlag)
 	BoringTagFormat = pipeline.NewStringFlagArgument(BoringTagFormatFlag)
 )
+
+// DockerVariantArguments are the base image and the tag format arguments of a docker.Variant.
+type DockerVariantArguments struct {
+	Image     pipeline.Argument
+	TagFormat pipeline.Argument
+}
+
+// DockerVariants has the arguments of each docker.Variant by the name of the variant.
+var DockerVariants = dockerVariantArguments(docker.Variants)
+
+func dockerVariantArguments(variants []docker.Variant) map[string]DockerVariantArguments {
+	args := make(map[string]DockerVariantArguments, len(variants))
+	for _, v := range variants {
+		args[v.Name] = DockerVariantArguments{
+			Image: pipeline.NewStringFlagArgument(&cli.StringFlag{
+				Name:  v.ImageFlag,
+				Usage: "The image to use as the base image when building the " + v.Name + " version of the Grafana docker image",
+				Value: v.DefaultImage,
+			}),
+			TagFormat: pipeline.NewStringFlagArgument(&cli.StringFlag{
+				Name:  v.TagFormatFlag,
+				Usage: "Provide a go template for formatting the docker tag(s) of the " + v.Name + " images",
+				Value: v.DefaultTagFormat,
+			}),
+		}
+	}
+
+	return args
+}
+
+// DockerVariantFlags returns the base image and tag format flags of every docker.Variant, in the order of docker.Variants.
+func DockerVariantFlags() []cli.Flag {
+	flags := make([]cli.Flag, 0, len(docker.Variants)*2)
+	for _, v := range docker.Variants {
+		a := DockerVariants[v.Name]
+		flags = append(flags, a.Image.Flags...)
+		flags = append(flags, a.TagFormat.Flags...)
+	}
+
+	return flags
+}
@@ -1,6 +1,12 @@
 package arguments
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/golang"
 	"github.com/grafana/grafana-build/pipeline"
 	"github.com/urfave/cli/v2"
 )
@@ -25,3 +31,115 @@ var ViceroyVersionFlag = &cli.StringFlag{
 }
 
 var ViceroyVersion = pipeline.NewStringFlagArgument(ViceroyVersionFlag)
+
+var BackendBinariesFlag = &cli.StringFlag{
+	Name:  "backend-binaries",
+	Usage: "Path to a JSON manifest of the Go main packages that are compiled for each package name, with their tags and ldflags. If not set, the binaries in the source of the Grafana version are built",
+}
+
+var BackendBinaries = pipeline.NewStringFlagArgument(BackendBinariesFlag)
+
+var GoModCacheFlag = &cli.StringFlag{
+	Name:  "go-mod-cache",
+	Usage: "Name of the cache volume for the Go module cache. If not set, the name has the hash of the go.sum of the Grafana source",
+}
+
+// GoModCache is the name of the Go module cache volume. If '--go-mod-cache' is not set, it is computed from the go.sum of the Grafana source
+// once, when it is first used, instead of every time a backend build container is created.
+var GoModCache = pipeline.Argument{
+	Name:         "go-mod-cache",
+	Description:  GoModCacheFlag.Usage,
+	ArgumentType: pipeline.ArgumentTypeString,
+	Flags: []cli.Flag{
+		GoModCacheFlag,
+	},
+	Requires: []pipeline.Argument{
+		GrafanaDirectory,
+	},
+	ValueFunc: func(ctx context.Context, opts *pipeline.ArgumentOpts) (any, error) {
+		if v := opts.CLIContext.String(GoModCacheFlag.Name); v != "" {
+			return v, nil
+		}
+
+		src, err := opts.State.Directory(ctx, GrafanaDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		goSum, err := src.File("go.sum").Contents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading go.sum for the module cache key: %w", err)
+		}
+
+		return golang.ModuleCacheKey(goSum), nil
+	},
+}
+
+var GoBuildCacheFlag = &cli.StringFlag{
+	Name:  "go-build-cache",
+	Usage: "Name of the cache volume for the Go build cache (GOCACHE). If not set, there is one volume for each Go version, distribution, and C toolchain",
+}
+
+var GoBuildCache = pipeline.NewStringFlagArgument(GoBuildCacheFlag)
+
+var GoTestPackagesFlag = &cli.StringFlag{
+	Name:  "go-test-packages",
+	Usage: "Comma-separated package patterns that the 'backend-test' artifact runs 'go test' for",
+	Value: "./pkg/...",
+}
+
+var GoTestPackages = pipeline.NewStringFlagArgument(GoTestPackagesFlag)
+
+var PGOProfileFlag = &cli.StringFlag{
+	Name:  "pgo-profile",
+	Usage: "Path or 'gs://' / 's3://' URL of the CPU profile that the backend of artifacts with the 'pgo' flag is optimized with",
+}
+
+// PGOProfile is the CPU profile from --pgo-profile, downloaded with the gcloud or aws credentials of the host if it's a URL.
+var PGOProfile = pipeline.Argument{
+	Name:         "pgo-profile",
+	Description:  PGOProfileFlag.Usage,
+	ArgumentType: pipeline.ArgumentTypeFile,
+	Flags: []cli.Flag{
+		PGOProfileFlag,
+	},
+	ValueFunc: func(ctx context.Context, opts *pipeline.ArgumentOpts) (any, error) {
+		u := opts.CLIContext.String(PGOProfileFlag.Name)
+		if u == "" {
+			return nil, fmt.Errorf("the 'pgo' flag requires a profile: %w", pipeline.ErrorFlagNotProvided)
+		}
+
+		storage, err := containers.StorageForURL(u, &containers.StorageOpts{
+			GCPOpts: containers.GCPOptsFromFlags(opts.CLIContext),
+			S3Opts:  containers.S3OptsFromFlags(opts.CLIContext),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return storage.Download(ctx, opts.Client, u)
+	},
+}
+
+// PGOProfileSha256 is the sha256 checksum of the PGOProfile. It's part of the name of the backends that are optimized with it.
+var PGOProfileSha256 = pipeline.Argument{
+	Name:         "pgo-profile-sha256",
+	Description:  "The sha256 checksum of the profile from --pgo-profile",
+	ArgumentType: pipeline.ArgumentTypeString,
+	Requires: []pipeline.Argument{
+		PGOProfile,
+	},
+	ValueFunc: func(ctx context.Context, opts *pipeline.ArgumentOpts) (any, error) {
+		profile, err := opts.State.File(ctx, PGOProfile)
+		if err != nil {
+			return nil, err
+		}
+
+		sum, err := containers.Sha256(opts.Client, profile).Contents(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.TrimSpace(sum), nil
+	},
+}
@@ -0,0 +1,41 @@
+package arguments
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/urfave/cli/v2"
+)
+
+var GoTestShardFlag = &cli.StringFlag{
+	Name:  "go-test-shard",
+	Usage: "The part of the packages that the 'backend-test' artifact tests, like '1/4' for the first of four shards",
+	Value: "1/1",
+}
+
+var GoTestShard = pipeline.NewStringFlagArgument(GoTestShardFlag)
+
+var FrontendTestShardFlag = &cli.StringFlag{
+	Name:  "frontend-test-shard",
+	Usage: "The part of the jest tests that the 'frontend-test' artifact runs, like '1/4' for the first of four shards",
+	Value: "1/1",
+}
+
+var FrontendTestShard = pipeline.NewStringFlagArgument(FrontendTestShardFlag)
+
+var FrontendLintShardFlag = &cli.StringFlag{
+	Name:  "frontend-lint-shard",
+	Usage: "The part of the lint scripts that the 'frontend-lint' artifact runs, like '1/2' for the first of two shards",
+	Value: "1/1",
+}
+
+var FrontendLintShard = pipeline.NewStringFlagArgument(FrontendLintShardFlag)
+
+var FrontendLintScriptsFlag = &cli.StringFlag{
+	Name:  "frontend-lint-scripts",
+	Usage: "Comma-separated package.json scripts that the 'frontend-lint' artifact runs",
+	Value: strings.Join(frontend.DefaultLintScripts, ","),
+}
+
+var FrontendLintScripts = pipeline.NewStringFlagArgument(FrontendLintScriptsFlag)
@@ -52,3 +52,17 @@ var YarnCacheDirectory = pipeline.Argument{
 		return cache, nil
 	},
 }
+
+var FrontendStatsBaselineFlag = &cli.StringFlag{
+	Name:  "frontend-stats-baseline",
+	Usage: "Path to the 'report.json' of a previous 'frontend-stats' artifact that the chunk sizes are compared to",
+}
+
+var FrontendStatsBaseline = pipeline.NewStringFlagArgument(FrontendStatsBaselineFlag)
+
+var FrontendSizeBudgetsFlag = &cli.StringFlag{
+	Name:  "frontend-size-budgets",
+	Usage: "Path to a JSON file with the size budgets of the frontend and its chunks that the 'frontend-stats' artifact checks",
+}
+
+var FrontendSizeBudgets = pipeline.NewStringFlagArgument(FrontendSizeBudgetsFlag)
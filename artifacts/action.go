@@ -6,43 +6,74 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/events"
+	"github.com/grafana/grafana-build/logging"
+	"github.com/grafana/grafana-build/otel"
 	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/retry"
+	"github.com/grafana/grafana-build/scan"
 	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
-func Action(r Registerer, c *cli.Context) error {
+func Action(r Registerer, c *cli.Context) (err error) {
+	ctx, span := tracer.Start(c.Context, "artifacts", trace.WithAttributes(attribute.StringSlice("artifacts", c.StringSlice("artifacts"))))
+	defer func() {
+		if err != nil {
+			otel.RecordFailed(span, err, "artifacts failed")
+		}
+		span.End()
+	}()
+
 	// ArtifactStrings represent an artifact with a list of boolean options, like
 	// targz:linux/amd64:enterprise
 	artifactStrings := c.StringSlice("artifacts")
 
-	logLevel := slog.LevelInfo
-	if c.Bool("verbose") {
-		logLevel = slog.LevelDebug
-	}
+	verbose := c.Bool("verbose")
+	logging.SetVerbose(verbose)
 
 	var (
-		ctx = c.Context
-		log = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-			Level: logLevel,
-		}))
-		parallel    = c.Int64("parallel")
+		log         = slog.Default()
 		destination = c.String("destination")
 		platform    = dagger.Platform(c.String("platform"))
 		verify      = c.Bool("verify")
 		checksum    = c.Bool("checksum")
+		scanEnabled = c.Bool("scan")
+		keepGoing   = c.Bool("keep-going")
 	)
 
 	if len(artifactStrings) == 0 {
 		return errors.New("no artifacts specified. At least 1 artifact is required using the '--artifact' or '-a' flag")
 	}
 
+	scanOpts, err := scanOptsFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	policies, err := retry.PoliciesFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	// The previous manifest is read before building so that a wrong path doesn't fail after the build.
+	var previous *SummaryManifest
+	if path := c.String("previous-manifest"); path != "" {
+		previous, err = ReadSummaryManifest(path)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Debug("Connecting to dagger daemon...")
 	daggerOpts := []dagger.ClientOpt{}
-	if logLevel == slog.LevelDebug {
+	if verbose {
 		daggerOpts = append(daggerOpts, dagger.WithLogOutput(os.Stderr))
 	}
 	client, err := dagger.Connect(ctx, daggerOpts...)
@@ -51,6 +82,10 @@ func Action(r Registerer, c *cli.Context) error {
 	}
 	log.Debug("Connected to dagger daemon")
 
+	if db := c.String("scan-db"); db != "" {
+		scanOpts.DB = client.Host().Directory(db)
+	}
+
 	var state pipeline.StateHandler = &pipeline.State{
 		Log:        log,
 		Client:     client,
@@ -85,7 +120,34 @@ func Action(r Registerer, c *cli.Context) error {
 		Store:    store,
 	}
 
+	// The summary records the durations, sizes, and results of every artifact, and is shown at the end, even if the build stopped early.
+	summary := &Summary{}
+	ctx = ContextWithSummary(ctx, summary)
+	defer func() {
+		if err := writeSummary(c, summary, previous); err != nil {
+			log.Warn("Error writing build summary", "error", err)
+		}
+	}()
+
+	// With '--keep-going', failures are collected instead of stopping the build, and are shown in a table at the end.
+	failures := &Failures{}
+	handle := func(v *pipeline.Artifact, action string, fn func() error) func() error {
+		return func() error {
+			err := fn()
+			if err == nil {
+				return nil
+			}
+
+			failures.Add(ctx, v, action, err)
+			if keepGoing {
+				return nil
+			}
+			return err
+		}
+	}
+
 	// Build each artifact and their dependencies, essentially constructing a dag using Dagger.
+	built := make([]*pipeline.Artifact, 0, len(artifacts))
 	for i, v := range artifacts {
 		filename, err := v.Handler.Filename(ctx)
 		if err != nil {
@@ -93,38 +155,126 @@ func Action(r Registerer, c *cli.Context) error {
 		}
 		log := log.With("filename", filename, "artifact", v.ArtifactString)
 		log.Info("Adding artifact to dag...")
-		if err := BuildArtifact(ctx, log, v, opts); err != nil {
+		events.Emit(ArtifactEvent(ctx, events.TypeStarted, v))
+		summary.Add(ctx, v)
+		// Building only adds the artifact to the dag, so it isn't retried. The work runs, and is retried, when the artifact is exported.
+		build := func() error {
+			return BuildArtifact(ctx, log, v, opts)
+		}
+		if err := handle(v, "build", build)(); err != nil {
 			return err
 		}
+		if failures.Has(v) {
+			log.Warn("Skipping artifact that could not be added to the dag")
+			continue
+		}
+		built = append(built, v)
 		log.Info("Done adding artifact")
 	}
 
-	wg := &errgroup.Group{}
-	sm := semaphore.NewWeighted(parallel)
+	// Without '--keep-going', the first failure cancels the context of the other actions.
+	wg, gctx := errgroup.WithContext(ctx)
+	s := SchedulerFromFlags(c)
 	log.Info("Exporting artifacts...")
-	// Export the files from the dag, causing the containers to trigger.
-	for _, v := range artifacts {
-		log := log.With("artifact", v.ArtifactString, "action", "export")
-		wg.Go(ExportArtifactFunc(ctx, client, sm, log, v, store, destination, checksum))
-	}
-	if verify {
+	for _, v := range built {
 		// Export the files from the dag, causing the containers to trigger.
-		for _, v := range artifacts {
-			log := log.With("artifact", v.ArtifactString, "action", "validate")
-			wg.Go(VerifyArtifactFunc(ctx, client, sm, log, v, store, destination))
+		// Each artifact is verified and scanned after it is exported, and with '--keep-going', artifacts that already failed are skipped.
+		actions := []func() error{
+			handle(v, "export", ExportArtifactFunc(gctx, client, s, log.With("artifact", v.ArtifactString, "action", "export"), v, store, destination, checksum, policies.For(retry.PhaseExport))),
+		}
+		if verify || pipeline.ArtifactAlwaysVerify(v) {
+			actions = append(actions, handle(v, "verify", VerifyArtifactFunc(gctx, client, s, log.With("artifact", v.ArtifactString, "action", "validate"), v, store, destination, policies.For(retry.PhaseVerify))))
+		}
+		if scanEnabled {
+			actions = append(actions, handle(v, "scan", ScanArtifactFunc(gctx, client, s, log.With("artifact", v.ArtifactString, "action", "scan"), v, store, destination, scanOpts, policies.For(retry.PhaseScan))))
+		}
+		wg.Go(failures.UntilFailed(v, actions...))
+	}
+
+	if err := wg.Wait(); err != nil {
+		return err
+	}
+
+	if failures.Len() != 0 {
+		fmt.Fprintf(os.Stderr, "\n%d of %d artifacts failed:\n\n", failures.Artifacts(), len(artifacts))
+		if err := failures.WriteTable(os.Stderr); err != nil {
+			return err
+		}
+		return failures.Err()
+	}
+
+	return nil
+}
+
+// writeSummary compares the summary to the previous manifest, shows it on stderr, and writes it to the '--summary-markdown' and '--summary-manifest' files.
+func writeSummary(c *cli.Context, s *Summary, previous *SummaryManifest) error {
+	for _, e := range s.Compare(previous, c.Float64("size-threshold")) {
+		slog.Warn("Size regression", "filename", e.Filename, "size", e.Size, "previous", e.PreviousSize, "growth", fmt.Sprintf("%.1f%%", e.Growth()))
+	}
+
+	fmt.Fprint(os.Stderr, "\nBuild summary:\n\n")
+	if err := s.WriteTable(os.Stderr); err != nil {
+		return err
+	}
+
+	if path := c.String("summary-markdown"); path != "" {
+		// Append so that the summary can be added to an existing file, like '$GITHUB_STEP_SUMMARY'.
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := s.WriteMarkdown(f); err != nil {
+			return err
 		}
 	}
 
-	return wg.Wait()
+	if path := c.String("summary-manifest"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := s.WriteJSON(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanOptsFromFlags(c *cli.Context) (*scan.Opts, error) {
+	format, err := scan.ParseFormat(c.String("scan-format"))
+	if err != nil {
+		return nil, err
+	}
+
+	severity := c.String("scan-severity")
+	if _, err := scan.SeveritiesAtOrAbove(severity); err != nil {
+		return nil, err
+	}
+
+	return &scan.Opts{
+		Image:    c.String("scan-image"),
+		Severity: severity,
+		Format:   format,
+	}, nil
 }
 
-func BuildArtifact(ctx context.Context, log *slog.Logger, a *pipeline.Artifact, opts *pipeline.ArtifactContainerOpts) error {
+// BuildArtifact adds the artifact and its dependencies to the dag. Each artifact gets a span that is nested in the span of the artifact that depends on it.
+func BuildArtifact(ctx context.Context, log *slog.Logger, a *pipeline.Artifact, opts *pipeline.ArtifactContainerOpts) (err error) {
+	ctx, t := startAction(ctx, "build", a)
+	defer func() { t.End(ctx, err) }()
+
 	store := opts.Store
 	exists, err := store.Exists(ctx, a)
 	if err != nil {
 		return err
 	}
+	t.Span.SetAttributes(attribute.Bool("cache.hit", exists))
+	t.Cached = exists
 	if exists {
+		t.Skip = true
 		return nil
 	}
 
@@ -192,18 +342,19 @@ func BuildArtifactDirectory(ctx context.Context, a *pipeline.Artifact, opts *pip
 	return a.Handler.BuildDir(ctx, builder, opts)
 }
 
-func ExportArtifactFunc(ctx context.Context, d *dagger.Client, sm *semaphore.Weighted, log *slog.Logger, v *pipeline.Artifact, store pipeline.ArtifactStore, dst string, checksum bool) func() error {
-	return func() error {
+func ExportArtifactFunc(ctx context.Context, d *dagger.Client, s *Scheduler, log *slog.Logger, v *pipeline.Artifact, store pipeline.ArtifactStore, dst string, checksum bool, policy retry.Policy) func() error {
+	return func() (err error) {
+		ctx, t := startAction(ctx, "export", v)
+		defer func() { t.End(ctx, err) }()
+
 		log.Info("Started exporting artifact...")
 
-		log.Info("Acquiring semaphore")
-		if err := sm.Acquire(ctx, 1); err != nil {
-			log.Info("Error acquiring semaphore", "error", err)
+		release, err := s.Acquire(ctx, log, "export", pipeline.ArtifactResources(ctx, v, "export"))
+		if err != nil {
 			return err
 		}
-		log.Info("Acquired semaphore")
-
-		defer sm.Release(1)
+		defer release()
+		t.Started()
 
 		filename, err := v.Handler.Filename(ctx)
 		if err != nil {
@@ -211,13 +362,21 @@ func ExportArtifactFunc(ctx context.Context, d *dagger.Client, sm *semaphore.Wei
 		}
 
 		log.Info("Exporting artifact")
-		paths, err := store.Export(ctx, d, v, dst, checksum)
+		var paths []string
+		err = policy.Do(ctx, log, func(ctx context.Context) error {
+			p, err := store.Export(ctx, d, v, dst, checksum)
+			paths = p
+			return err
+		})
+		t.Span.SetAttributes(attribute.Int("attempts", retry.Attempts(err)))
 		if err != nil {
 			return fmt.Errorf("error exporting artifact '%s': %w", filename, err)
 		}
 
-		for _, v := range paths {
-			fmt.Fprintf(Stdout, "%s\n", v)
+		t.Event.Paths = paths
+		for _, path := range paths {
+			recordSize(ctx, v, path)
+			fmt.Fprintf(Stdout, "%s\n", path)
 		}
 
 		log.Info("Done exporting artifact")
@@ -251,21 +410,90 @@ func verifyArtifact(ctx context.Context, client *dagger.Client, v *pipeline.Arti
 	return nil
 }
 
-func VerifyArtifactFunc(ctx context.Context, d *dagger.Client, sm *semaphore.Weighted, log *slog.Logger, v *pipeline.Artifact, store pipeline.ArtifactStore, dst string) func() error {
-	return func() error {
+func VerifyArtifactFunc(ctx context.Context, d *dagger.Client, s *Scheduler, log *slog.Logger, v *pipeline.Artifact, store pipeline.ArtifactStore, dst string, policy retry.Policy) func() error {
+	return func() (err error) {
+		ctx, t := startAction(ctx, "verify", v)
+		defer func() { t.End(ctx, err) }()
+
 		log.Info("Started verifying artifact...")
 
-		log.Info("Acquiring semaphore")
-		if err := sm.Acquire(ctx, 1); err != nil {
-			log.Info("Error acquiring semaphore", "error", err)
+		release, err := s.Acquire(ctx, log, "verify", pipeline.ArtifactResources(ctx, v, "verify"))
+		if err != nil {
+			return err
+		}
+		defer release()
+		t.Started()
+
+		err = policy.Do(ctx, log, func(ctx context.Context) error {
+			return verifyArtifact(ctx, d, v, store)
+		})
+		t.Span.SetAttributes(attribute.Int("attempts", retry.Attempts(err)))
+		return err
+	}
+}
+
+// ScanArtifactFunc scans the artifact for vulnerabilities and exports the report next to the artifact in the destination.
+// Artifacts that do not implement pipeline.ArtifactScanner, and directory artifacts, are not scanned.
+func ScanArtifactFunc(ctx context.Context, d *dagger.Client, s *Scheduler, log *slog.Logger, v *pipeline.Artifact, store pipeline.ArtifactStore, dst string, opts *scan.Opts, policy retry.Policy) func() error {
+	return func() (err error) {
+		if !pipeline.ArtifactCanScan(v) || v.Type != pipeline.ArtifactTypeFile {
+			return nil
+		}
+		scanner := v.Handler.(pipeline.ArtifactScanner)
+
+		ctx, t := startAction(ctx, "scan", v)
+		defer func() { t.End(ctx, err) }()
+
+		log.Info("Started scanning artifact...")
+
+		release, err := s.Acquire(ctx, log, "scan", pipeline.ArtifactResources(ctx, v, "scan"))
+		if err != nil {
+			return err
+		}
+		defer release()
+		t.Started()
+
+		filename, err := v.Handler.Filename(ctx)
+		if err != nil {
+			return fmt.Errorf("error processing artifact string '%s': %w", v.ArtifactString, err)
+		}
+
+		file, err := store.File(ctx, v)
+		if err != nil {
 			return err
 		}
-		log.Info("Acquired semaphore")
-		defer sm.Release(1)
 
-		if err := verifyArtifact(ctx, d, v, store); err != nil {
+		var report *scan.Report
+		err = policy.Do(ctx, log, func(ctx context.Context) error {
+			r, err := scanner.ScanFile(ctx, d, file, opts)
+			report = r
 			return err
+		})
+		t.Span.SetAttributes(attribute.Int("attempts", retry.Attempts(err)))
+		if err != nil {
+			return fmt.Errorf("error scanning artifact '%s': %w", filename, err)
+		}
+		if report == nil {
+			return nil
+		}
+
+		path := filepath.Join(dst, fmt.Sprintf("%s.%s", filename, opts.Format.Ext()))
+		if _, err := report.File.Export(ctx, path); err != nil {
+			return fmt.Errorf("error exporting scan report for '%s': %w", filename, err)
+		}
+		fmt.Fprintf(Stdout, "%s\n", path)
+
+		t.Span.SetAttributes(attribute.Int("scan.vulnerabilities", len(report.Vulnerabilities)))
+		t.Event.Paths = []string{path}
+		if n := len(report.Vulnerabilities); n != 0 {
+			ids := make([]string, n)
+			for i, vuln := range report.Vulnerabilities {
+				ids[i] = fmt.Sprintf("%s (%s %s)", vuln.VulnerabilityID, vuln.PkgName, vuln.Severity)
+			}
+			return fmt.Errorf("found %d vulnerabilities with severity %s or higher in '%s': %s", n, opts.Severity, filename, strings.Join(ids, ", "))
 		}
+
+		log.Info("Done scanning artifact")
 		return nil
 	}
 }
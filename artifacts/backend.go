@@ -2,6 +2,7 @@ package artifacts
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"path/filepath"
 
@@ -19,11 +20,20 @@ var (
 		arguments.EnterpriseDirectory,
 		arguments.GoVersion,
 		arguments.ViceroyVersion,
+		arguments.BackendBinaries,
+		arguments.GoModCache,
+		arguments.GoBuildCache,
+		arguments.PGOProfile,
 	}
 
 	BackendFlags = flags.JoinFlags(
 		flags.PackageNameFlags,
 		flags.DistroFlags(),
+		[]pipeline.Flag{
+			flags.PGOFlag,
+			flags.RaceFlag,
+			flags.CoverFlag,
+		},
 	)
 )
 
@@ -48,11 +58,24 @@ type Backend struct {
 }
 
 func (b *Backend) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	modCache, err := opts.State.String(ctx, arguments.GoModCache)
+	if err != nil {
+		return nil, err
+	}
+	buildCache, err := opts.State.String(ctx, arguments.GoBuildCache)
+	if err != nil {
+		return nil, err
+	}
+
+	bopts := *b.BuildOpts
+	bopts.GoModCache = modCache
+	bopts.GoBuildCache = buildCache
+
 	return backend.Builder(
 		opts.Client,
 		opts.Log,
 		b.Distribution,
-		b.BuildOpts,
+		&bopts,
 		opts.Platform,
 		b.Src,
 		b.GoVersion,
@@ -100,8 +123,27 @@ func (b *Backend) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishD
 // also affect the filename to ensure that there are no collisions.
 // For example, the backend for `linux/amd64` and `linux/arm64` should not both produce a `bin` folder, they should produce a
 // `bin/linux-amd64` folder and a `bin/linux-arm64` folder. Callers can mount this as `bin` or whatever if they want.
+// Instrumented backends have the instrumentation in their name, like `bin/grafana/linux/amd64/dynamic-race`.
+// Backends that are optimized with a PGO profile have the checksum of the profile in their name, like `bin/grafana/linux/amd64-pgo-0123456789ab`.
 func (b *Backend) Filename(ctx context.Context) (string, error) {
-	return filepath.Join("bin", string(b.Name), string(b.Distribution)), nil
+	name := string(b.Distribution)
+	if s := b.BuildOpts.Instrumentation.Suffix(); s != "" {
+		name = name + "-" + s
+	}
+	if b.BuildOpts.PGOProfile != nil {
+		name = name + "-" + b.BuildOpts.PGOProfile.Suffix()
+	}
+
+	return filepath.Join("bin", string(b.Name), name), nil
+}
+
+// Resources declares that compiling the backend is CPU-heavy. Cross-compiling in a viceroy container counts twice.
+func (b *Backend) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceBackend, Weight: backendWeight(b.Distribution)}}
 }
 
 func (b *Backend) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
@@ -115,17 +157,84 @@ func (b *Backend) VerifyDirectory(ctx context.Context, client *dagger.Client, di
 }
 
 type NewBackendOpts struct {
-	Name           packages.Name
-	Enterprise     bool
-	Src            *dagger.Directory
-	Distribution   backend.Distribution
-	GoVersion      string
-	ViceroyVersion string
-	Version        string
-	Experiments    []string
-	Tags           []string
-	Static         bool
-	WireTag        string
+	Name            packages.Name
+	Enterprise      bool
+	Src             *dagger.Directory
+	Distribution    backend.Distribution
+	GoVersion       string
+	ViceroyVersion  string
+	Version         string
+	Experiments     []string
+	Tags            []string
+	Static          bool
+	WireTag         string
+	Binaries        []backend.Binary
+	PGOProfile      *backend.PGOProfile
+	Instrumentation backend.Instrumentation
+}
+
+// BackendBinaries returns the binaries for the package name from the --backend-binaries manifest, or nil if it isn't set so that the binaries of the version are built.
+func BackendBinaries(ctx context.Context, state pipeline.StateHandler, name packages.Name, version string) ([]backend.Binary, error) {
+	path, err := state.String(ctx, arguments.BackendBinaries)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	m, err := backend.ReadBinariesManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Binaries(string(name), version), nil
+}
+
+// BackendInstrumentation returns the instrumentation from the 'race' and 'cover' flags of the artifact.
+// It returns ErrorUnsupportedDistribution if the race detector is requested for a distribution that doesn't support it.
+func BackendInstrumentation(artifact string, options *pipeline.OptionsHandler, p PackageDetails) (backend.Instrumentation, error) {
+	race, err := options.Bool(flags.Race)
+	if err != nil {
+		return backend.Instrumentation{}, err
+	}
+	cover, err := options.Bool(flags.Cover)
+	if err != nil {
+		return backend.Instrumentation{}, err
+	}
+	if race && !p.Capabilities.Race {
+		return backend.Instrumentation{}, fmt.Errorf("%s: race builds are not supported for '%s': %w", artifact, p.Distribution, backend.ErrorUnsupportedDistribution)
+	}
+
+	return backend.Instrumentation{
+		Race:  race,
+		Cover: cover,
+	}, nil
+}
+
+// BackendPGOProfile returns the profile from --pgo-profile if the artifact has the 'pgo' flag, or nil if it doesn't.
+func BackendPGOProfile(ctx context.Context, state pipeline.StateHandler, options *pipeline.OptionsHandler) (*backend.PGOProfile, error) {
+	pgo, err := options.Bool(flags.PGO)
+	if err != nil {
+		return nil, err
+	}
+	if !pgo {
+		return nil, nil
+	}
+
+	file, err := state.File(ctx, arguments.PGOProfile)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := state.String(ctx, arguments.PGOProfileSha256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backend.PGOProfile{
+		File:   file,
+		Sha256: sum,
+	}, nil
 }
 
 func NewBackendFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
@@ -174,6 +283,21 @@ func NewBackendFromString(ctx context.Context, log *slog.Logger, artifact string
 		return nil, err
 	}
 
+	binaries, err := BackendBinaries(ctx, state, p.Name, p.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	pgo, err := BackendPGOProfile(ctx, state, options)
+	if err != nil {
+		return nil, err
+	}
+
+	instrumentation, err := BackendInstrumentation(artifact, options, p)
+	if err != nil {
+		return nil, err
+	}
+
 	bopts := &backend.BuildOpts{
 		Version:           p.Version,
 		Enterprise:        p.Enterprise,
@@ -181,6 +305,9 @@ func NewBackendFromString(ctx context.Context, log *slog.Logger, artifact string
 		Static:            static,
 		WireTag:           wireTag,
 		Tags:              tags,
+		Binaries:          binaries,
+		PGOProfile:        pgo,
+		Instrumentation:   instrumentation,
 	}
 
 	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
@@ -206,6 +333,9 @@ func NewBackend(ctx context.Context, log *slog.Logger, artifact string, opts *Ne
 		Tags:              opts.Tags,
 		Static:            opts.Static,
 		WireTag:           opts.WireTag,
+		Binaries:          opts.Binaries,
+		PGOProfile:        opts.PGOProfile,
+		Instrumentation:   opts.Instrumentation,
 	}
 
 	log.Info("Initializing backend artifact with options", "static", opts.Static, "version", opts.Version, "name", opts.Name, "distro", opts.Distribution)
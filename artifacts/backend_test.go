@@ -0,0 +1,49 @@
+package artifacts_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+func TestBackendInstrumentation(t *testing.T) {
+	cases := map[string]struct {
+		Distribution backend.Distribution
+		Expect       backend.Instrumentation
+		Err          error
+	}{
+		"targz:grafana:linux/amd64/dynamic:race":     {backend.DistLinuxAMD64Dynamic, backend.Instrumentation{Race: true}, nil},
+		"targz:grafana:linux/amd64:cover":            {backend.DistLinuxAMD64, backend.Instrumentation{Cover: true}, nil},
+		"backend:grafana:darwin/arm64:race:cover":    {backend.DistDarwinARM64, backend.Instrumentation{Race: true, Cover: true}, nil},
+		"targz:grafana:linux/amd64":                  {backend.DistLinuxAMD64, backend.Instrumentation{}, nil},
+		"targz:grafana:linux/amd64:race":             {backend.DistLinuxAMD64, backend.Instrumentation{}, backend.ErrorUnsupportedDistribution},
+		"backend:enterprise:linux/arm/v7:race:cover": {backend.DistLinuxARMv7, backend.Instrumentation{}, backend.ErrorUnsupportedDistribution},
+	}
+
+	for artifact, c := range cases {
+		t.Run(artifact, func(t *testing.T) {
+			options, err := pipeline.ParseFlags(artifact, artifacts.TargzFlags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			capabilities, err := backend.DistroCapabilities(c.Distribution)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			i, err := artifacts.BackendInstrumentation(artifact, options, artifacts.PackageDetails{
+				Distribution: c.Distribution,
+				Capabilities: capabilities,
+			})
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("expected error '%v' but got '%v'", c.Err, err)
+			}
+			if i != c.Expect {
+				t.Errorf("expected %+v but got %+v", c.Expect, i)
+			}
+		})
+	}
+}
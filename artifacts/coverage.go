@@ -0,0 +1,160 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/arguments"
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/e2e"
+	"github.com/grafana/grafana-build/flags"
+	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/packages"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+var CoverageInitializer = Initializer{
+	InitializerFunc: NewCoverageFromString,
+	Arguments:       TargzArguments,
+}
+
+// Coverage runs the e2e tests against a tarball that is built with the 'cover' flag, with GOCOVERDIR set, and produces a directory with the coverage data.
+// Verifying the tarball only returns an error, so the coverage data can't be exported from there.
+// Grafana runs in the cypress container next to the tests, so that its coverage counters are written to a directory in that container when it stops.
+type Coverage struct {
+	Distribution backend.Distribution
+	Tarball      *pipeline.Artifact
+
+	// Src is the Grafana source with the e2e tests.
+	Src       *dagger.Directory
+	YarnCache *dagger.CacheVolume
+}
+
+func (c *Coverage) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
+	return []*pipeline.Artifact{
+		c.Tarball,
+	}, nil
+}
+
+func (c *Coverage) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	nodeVersion, err := frontend.NodeVersion(opts.Client, c.Src).Stdout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node version from source code: %w", err)
+	}
+
+	return e2e.CoverageBuilder(opts.Client, c.Src, c.YarnCache, nodeVersion), nil
+}
+
+func (c *Coverage) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
+	// Not a file
+	return nil, nil
+}
+
+func (c *Coverage) BuildDir(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.Directory, error) {
+	targz, err := opts.Store.File(ctx, c.Tarball)
+	if err != nil {
+		return nil, err
+	}
+
+	return e2e.Coverage(builder, containers.ExtractedArchive(opts.Client, targz)), nil
+}
+
+func (c *Coverage) Publisher(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (c *Coverage) PublishFile(ctx context.Context, opts *pipeline.ArtifactPublishFileOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (c *Coverage) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (c *Coverage) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Not a file
+	return nil
+}
+
+// AlwaysVerify fails the artifact when the e2e tests fail, after the coverage data is exported, even without '--verify'.
+func (c *Coverage) AlwaysVerify() bool {
+	return true
+}
+
+// VerifyDirectory fails if the e2e tests failed. The output is in the 'e2e.log' and 'grafana.log' of the exported directory.
+func (c *Coverage) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
+	return verifyExitCode(ctx, dir.File(e2e.CoverageExitCodeFile), "e2e tests")
+}
+
+// Filename returns 'coverage/<name of the tarball>', like 'coverage/grafana-cover_10.2.0_linux_amd64'.
+func (c *Coverage) Filename(ctx context.Context) (string, error) {
+	name, err := c.Tarball.Handler.Filename(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join("coverage", packages.WithoutExt(name)), nil
+}
+
+// Resources declares that running the e2e tests uses a browser, like verifying a package.
+func (c *Coverage) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return packageResources(c.Distribution, "verify")
+}
+
+// NewCoverageFromString returns the coverage artifact of the tarball with the same flags. The 'cover' flag is added if it's not there.
+// Grafana runs in the cypress container, so only linux/amd64 tarballs are supported.
+func NewCoverageFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, TargzFlags)
+	if err != nil {
+		return nil, err
+	}
+	p, err := GetPackageDetails(ctx, options, state)
+	if err != nil {
+		return nil, err
+	}
+	if system, arch := backend.OSAndArch(p.Distribution); system != "linux" || arch != "amd64" {
+		return nil, fmt.Errorf("%s: coverage can't be collected for '%s': %w", artifact, p.Distribution, backend.ErrorUnsupportedDistribution)
+	}
+
+	cover, err := options.Bool(flags.Cover)
+	if err != nil {
+		return nil, err
+	}
+	tarballArtifact := artifact
+	if !cover {
+		tarballArtifact += ":" + flags.CoverFlag.Name
+	}
+	tarball, err := NewTarballFromString(ctx, log, tarballArtifact, state)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := state.Directory(ctx, arguments.GrafanaDirectory)
+	if err != nil {
+		return nil, err
+	}
+	yarnCache, err := state.CacheVolume(ctx, arguments.YarnCacheDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
+		ArtifactString: artifact,
+		Type:           pipeline.ArtifactTypeDirectory,
+		Flags:          TargzFlags,
+		Handler: &Coverage{
+			Distribution: p.Distribution,
+			Tarball:      tarball,
+			Src:          src,
+			YarnCache:    yarnCache,
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package artifacts_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+func TestCoverageFilename(t *testing.T) {
+	tarball := &pipeline.Artifact{Handler: &artifacts.Tarball{
+		Name:            "grafana",
+		Version:         "10.2.0",
+		BuildID:         "abc123",
+		Distribution:    backend.DistLinuxAMD64,
+		Instrumentation: backend.Instrumentation{Cover: true},
+	}}
+
+	name, err := (&artifacts.Coverage{Tarball: tarball}).Filename(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "coverage/grafana-cover_10.2.0_abc123_linux_amd64"; name != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, name)
+	}
+}
@@ -0,0 +1,123 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/retry"
+)
+
+// A Failure is an action on an artifact that failed.
+type Failure struct {
+	Artifact string
+	Filename string
+	Action   string
+	Err      error
+}
+
+// Failures collects the failures of every artifact when the '--keep-going' flag is set. It is safe to use from multiple goroutines.
+type Failures struct {
+	mu   sync.Mutex
+	list []Failure
+}
+
+func (f *Failures) Add(ctx context.Context, a *pipeline.Artifact, action string, err error) {
+	filename, _ := a.Handler.Filename(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = append(f.list, Failure{
+		Artifact: a.ArtifactString,
+		Filename: filename,
+		Action:   action,
+		Err:      err,
+	})
+}
+
+// Has returns true if any action on the artifact failed.
+func (f *Failures) Has(a *pipeline.Artifact) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, v := range f.list {
+		if v.Artifact == a.ArtifactString {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UntilFailed returns a function that runs the actions on the artifact in order and stops at the first one that returns an error.
+// With '--keep-going', failed actions don't return errors, so the remaining actions are also skipped once the artifact has any failure.
+func (f *Failures) UntilFailed(a *pipeline.Artifact, actions ...func() error) func() error {
+	return func() error {
+		for _, fn := range actions {
+			if f.Has(a) {
+				return nil
+			}
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (f *Failures) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.list)
+}
+
+// Artifacts returns the number of artifacts that had at least one failure.
+func (f *Failures) Artifacts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	artifacts := map[string]bool{}
+	for _, v := range f.list {
+		artifacts[v.Artifact] = true
+	}
+
+	return len(artifacts)
+}
+
+// Err joins the errors of every failure, or returns nil if there were none.
+func (f *Failures) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	errs := make([]error, len(f.list))
+	for i, v := range f.list {
+		errs[i] = fmt.Errorf("%s '%s': %w", v.Action, v.Artifact, v.Err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// WriteTable writes a summary of every failure with the first line of its error.
+func (f *Failures) WriteTable(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ARTIFACT\tACTION\tATTEMPTS\tERROR")
+	for _, v := range f.list {
+		err := v.Err
+		var re *retry.Error
+		if errors.As(err, &re) {
+			err = re.Err
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", v.Artifact, v.Action, retry.Attempts(v.Err), retry.Summary(err))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,90 @@
+package artifacts_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/retry"
+)
+
+func TestFailures(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		failed = &artifacts.Failures{}
+		targz  = &pipeline.Artifact{ArtifactString: "targz:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.tar.gz"}}
+		deb    = &pipeline.Artifact{ArtifactString: "deb:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.deb"}}
+		zip    = &pipeline.Artifact{ArtifactString: "zip:grafana:windows/amd64", Handler: &filenameHandler{filename: "grafana.zip"}}
+	)
+
+	failed.Add(ctx, targz, "export", &retry.Error{Attempts: 3, Err: errors.New("i/o timeout\nlots of output")})
+	failed.Add(ctx, targz, "verify", errors.New("health check failed"))
+	failed.Add(ctx, deb, "export", errors.New("exit code 1"))
+
+	if n := failed.Len(); n != 3 {
+		t.Errorf("expected 3 failures but got %d", n)
+	}
+	if n := failed.Artifacts(); n != 2 {
+		t.Errorf("expected 2 failed artifacts but got %d", n)
+	}
+	if !failed.Has(deb) || failed.Has(zip) {
+		t.Errorf("expected only the failed artifacts to have failures")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := failed.WriteTable(buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows but got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], "export") || !strings.Contains(lines[1], "3") || !strings.HasSuffix(lines[1], "i/o timeout") {
+		t.Errorf("expected the first row to show the attempts and the first line of the error but got '%s'", lines[1])
+	}
+
+	if err := failed.Err(); err == nil || !strings.Contains(err.Error(), "health check failed") {
+		t.Errorf("expected the joined error to contain every failure but got %v", err)
+	}
+}
+
+func TestFailuresUntilFailed(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		failed = &artifacts.Failures{}
+		targz  = &pipeline.Artifact{ArtifactString: "targz:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.tar.gz"}}
+		ran    = []string{}
+	)
+
+	action := func(name string, err error) func() error {
+		return func() error {
+			ran = append(ran, name)
+			if err != nil {
+				// This is what happens with '--keep-going'; the failure is recorded instead of returned.
+				failed.Add(ctx, targz, name, err)
+			}
+			return nil
+		}
+	}
+
+	fn := failed.UntilFailed(targz, action("export", errors.New("exit code 1")), action("verify", nil), action("scan", nil))
+	if err := fn(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ran, ",") != "export" {
+		t.Errorf("expected verify and scan to be skipped after the export failed, but ran %v", ran)
+	}
+
+	stop := errors.New("stop")
+	fn = (&artifacts.Failures{}).UntilFailed(targz, func() error { return stop }, func() error {
+		t.Error("expected the second action to be skipped")
+		return nil
+	})
+	if err := fn(); !errors.Is(err, stop) {
+		t.Errorf("expected the first error to be returned but got %v", err)
+	}
+}
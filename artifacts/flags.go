@@ -6,6 +6,7 @@ import (
 	"log/slog"
 
 	"github.com/grafana/grafana-build/cmd/flags"
+	"github.com/grafana/grafana-build/retry"
 	"github.com/urfave/cli/v2"
 )
 
@@ -41,7 +42,12 @@ func ArtifactFlags(r Registerer) []cli.Flag {
 			flags.Platform,
 		},
 		flags.PublishFlags,
+		flags.ScanFlags,
 		flags.ConcurrencyFlags,
+		flags.ResourceFlags(),
+		flags.RetryFlags(retry.PhaseExport, retry.PhaseVerify, retry.PhaseScan),
+		flags.FailureFlags,
+		flags.SummaryFlags,
 		[]cli.Flag{
 			flags.Verbose,
 		},
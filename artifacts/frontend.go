@@ -79,6 +79,15 @@ func (f *Frontend) Filename(ctx context.Context) (string, error) {
 	return filepath.Join(f.Version, n, "public"), nil
 }
 
+// Resources declares that building the frontend is memory-heavy.
+func (f *Frontend) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
 func (f *Frontend) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
 	// Should never be called since this isn't a File.
 	return nil
@@ -0,0 +1,191 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/arguments"
+	"github.com/grafana/grafana-build/flags"
+	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/packages"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+var (
+	FrontendStatsFlags     = flags.PackageNameFlags
+	FrontendStatsArguments = []pipeline.Argument{
+		arguments.YarnCacheDirectory,
+		arguments.FrontendStatsBaseline,
+		arguments.FrontendSizeBudgets,
+	}
+)
+
+var FrontendStatsInitializer = Initializer{
+	InitializerFunc: NewFrontendStatsFromString,
+	Arguments:       FrontendStatsArguments,
+}
+
+var ErrorSizeBudgetExceeded = errors.New("frontend size budget exceeded")
+
+// FrontendStats builds the frontend with webpack stats and produces a directory with the stats and a per-chunk size report as JSON and Markdown.
+type FrontendStats struct {
+	Name      packages.Name
+	Version   string
+	Src       *dagger.Directory
+	YarnCache *dagger.CacheVolume
+
+	// Baseline and Budgets are nil if they weren't provided.
+	Baseline *frontend.StatsReport
+	Budgets  *frontend.SizeBudgets
+}
+
+// The frontend stats do not have any artifact dependencies.
+func (f *FrontendStats) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
+	return nil, nil
+}
+
+// Builder will return a node.js container that matches the .nvmrc in the Grafana source repository
+func (f *FrontendStats) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	return FrontendBuilder(ctx, f.Src, f.YarnCache, opts)
+}
+
+func (f *FrontendStats) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
+	// Not a file
+	return nil, nil
+}
+
+// BuildDir builds the frontend and writes the reports in the builder, so that nothing runs until the directory is exported.
+func (f *FrontendStats) BuildDir(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.Directory, error) {
+	return frontend.Stats(builder, f.Baseline, f.Budgets)
+}
+
+func (f *FrontendStats) Publisher(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendStats) PublishFile(ctx context.Context, opts *pipeline.ArtifactPublishFileOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendStats) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendStats) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Not a file
+	return nil
+}
+
+// AlwaysVerify fails the artifact when a size budget is exceeded, after the report is exported, even without '--verify'.
+// Without budgets there is nothing to verify.
+func (f *FrontendStats) AlwaysVerify() bool {
+	return f.Budgets != nil
+}
+
+// VerifyDirectory fails if the report has size budget violations.
+func (f *FrontendStats) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
+	b, err := dir.File("report.json").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := &frontend.StatsReport{}
+	if err := json.Unmarshal([]byte(b), report); err != nil {
+		return err
+	}
+	if len(report.Violations) != 0 {
+		return fmt.Errorf("%s: %w", strings.Join(report.Violations, "; "), ErrorSizeBudgetExceeded)
+	}
+
+	return nil
+}
+
+// Filename should return a deterministic file or folder name that this build will produce.
+// The baseline and the budgets aren't in the name because they're the same for every frontend-stats artifact of a run.
+func (f *FrontendStats) Filename(ctx context.Context) (string, error) {
+	return filepath.Join(f.Version, string(f.Name), "frontend-stats"), nil
+}
+
+// Resources declares that building the frontend is memory-heavy.
+func (f *FrontendStats) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
+func NewFrontendStatsFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, FrontendStatsFlags)
+	if err != nil {
+		return nil, err
+	}
+	name, err := options.String(flags.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	enterprise, err := options.Bool(flags.Enterprise)
+	if err != nil {
+		return nil, err
+	}
+	src, err := GrafanaDir(ctx, state, enterprise)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := state.CacheVolume(ctx, arguments.YarnCacheDirectory)
+	if err != nil {
+		return nil, err
+	}
+	version, err := state.String(ctx, arguments.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	baselinePath, err := state.String(ctx, arguments.FrontendStatsBaseline)
+	if err != nil {
+		return nil, err
+	}
+	budgetsPath, err := state.String(ctx, arguments.FrontendSizeBudgets)
+	if err != nil {
+		return nil, err
+	}
+
+	// The baseline and the budgets are read here so that a wrong path doesn't fail after the build.
+	var (
+		baseline *frontend.StatsReport
+		budgets  *frontend.SizeBudgets
+	)
+	if baselinePath != "" {
+		baseline, err = frontend.ReadStatsReport(baselinePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if budgetsPath != "" {
+		budgets, err = frontend.ReadSizeBudgets(budgetsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("Initializing frontend stats artifact with options", "name", name, "version", version, "baseline", baseline != nil, "budgets", budgets != nil)
+	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
+		ArtifactString: artifact,
+		Type:           pipeline.ArtifactTypeDirectory,
+		Flags:          FrontendStatsFlags,
+		Handler: &FrontendStats{
+			Name:      packages.Name(name),
+			Version:   version,
+			Src:       src,
+			YarnCache: cache,
+			Baseline:  baseline,
+			Budgets:   budgets,
+		},
+	})
+}
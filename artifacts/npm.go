@@ -83,6 +83,15 @@ func (f *NPMPackages) Filename(ctx context.Context) (string, error) {
 	return filepath.Join(f.Version, "npm-packages"), nil
 }
 
+// Resources declares that building the npm packages is memory-heavy.
+func (f *NPMPackages) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
 func NewNPMPackagesFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
 	grafanaDir, err := GrafanaDir(ctx, state, false)
 	if err != nil {
@@ -2,6 +2,7 @@ package artifacts
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"dagger.io/dagger"
@@ -38,6 +39,8 @@ type Deb struct {
 	NameOverride string
 
 	Tarball *pipeline.Artifact
+	// PGOProfile is the profile that the backend in the tarball is optimized with, if any. It is part of the filename, like the tarball's.
+	PGOProfile *backend.PGOProfile
 
 	// Src is the source tree of Grafana. This should only be used in the verify function.
 	Src       *dagger.Directory
@@ -114,10 +117,23 @@ func (d *Deb) Filename(ctx context.Context) (string, error) {
 		name = packages.Name(d.NameOverride)
 	}
 
-	return packages.FileName(name, d.Version, d.BuildID, d.Distribution, "deb")
+	return packages.FileName(pgoName(name, d.PGOProfile), d.Version, d.BuildID, d.Distribution, "deb")
+}
+
+// Resources declares the resources used by the deb. Verifying runs e2e tests in a browser, which counts twice.
+func (d *Deb) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if c, _ := backend.DistroCapabilities(d.Distribution); action == "verify" && !c.Verify {
+		return nil
+	}
+
+	return packageResources(d.Distribution, action)
 }
 
 func (d *Deb) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Verification is skipped for the distributions that don't have a debian image to install the package in, like riscv64.
+	if c, _ := backend.DistroCapabilities(d.Distribution); !c.Verify {
+		return nil
+	}
 	return fpm.VerifyDeb(ctx, client, file, d.Src, d.YarnCache, d.Distribution, d.Enterprise)
 }
 
@@ -126,11 +142,18 @@ func (d *Deb) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *d
 }
 
 func NewDebFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, DebFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectInstrumentation(artifact, options); err != nil {
+		return nil, err
+	}
 	tarball, err := NewTarballFromString(ctx, log, artifact, state)
 	if err != nil {
 		return nil, err
 	}
-	options, err := pipeline.ParseFlags(artifact, DebFlags)
+	pgo, err := BackendPGOProfile(ctx, state, options)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +161,9 @@ func NewDebFromString(ctx context.Context, log *slog.Logger, artifact string, st
 	if err != nil {
 		return nil, err
 	}
+	if p.Capabilities.DebArch == "" {
+		return nil, fmt.Errorf("%s: deb packages can't be built for '%s': %w", artifact, p.Distribution, backend.ErrorUnsupportedDistribution)
+	}
 	src, err := state.Directory(ctx, arguments.GrafanaDirectory)
 	if err != nil {
 		return nil, err
@@ -164,6 +190,7 @@ func NewDebFromString(ctx context.Context, log *slog.Logger, artifact string, st
 			Distribution: p.Distribution,
 			Enterprise:   p.Enterprise,
 			Tarball:      tarball,
+			PGOProfile:   pgo,
 			Src:          src,
 			YarnCache:    yarnCache,
 			NameOverride: debname,
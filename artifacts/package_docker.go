@@ -2,6 +2,7 @@ package artifacts
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"dagger.io/dagger"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/grafana-build/flags"
 	"github.com/grafana/grafana-build/packages"
 	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/scan"
 )
 
 var (
@@ -19,12 +21,9 @@ var (
 		[]pipeline.Argument{
 			arguments.DockerRegistry,
 			arguments.DockerOrg,
-			arguments.AlpineImage,
-			arguments.UbuntuImage,
-			arguments.TagFormat,
-			arguments.UbuntuTagFormat,
 			arguments.BoringTagFormat,
 		},
+		dockerVariantArguments(docker.Variants),
 	)
 	DockerFlags = flags.JoinFlags(
 		TargzFlags,
@@ -32,6 +31,17 @@ var (
 	)
 )
 
+// dockerVariantArguments returns the base image and tag format arguments of each variant that can be selected with the 'base=<name>' flag.
+func dockerVariantArguments(variants []docker.Variant) []pipeline.Argument {
+	args := make([]pipeline.Argument, 0, len(variants)*2)
+	for _, v := range variants {
+		a := arguments.DockerVariants[v.Name]
+		args = append(args, a.Image, a.TagFormat)
+	}
+
+	return args
+}
+
 var DockerInitializer = Initializer{
 	InitializerFunc: NewDockerFromString,
 	Arguments:       DockerArguments,
@@ -45,7 +55,7 @@ type Docker struct {
 	Distro     backend.Distribution
 	Enterprise bool
 
-	Ubuntu       bool
+	Variant      docker.Variant
 	Registry     string
 	Repositories []string
 	Org          string
@@ -53,6 +63,8 @@ type Docker struct {
 	TagFormat    string
 
 	Tarball *pipeline.Artifact
+	// PGOProfile is the profile that the backend in the tarball is optimized with, if any. It is part of the filename, like the tarball's.
+	PGOProfile *backend.PGOProfile
 
 	// Src is the Grafana source code for running e2e tests when validating.
 	// The grafana source should not be used for anything else when building a docker image. All files in the Docker image, including the Dockerfile, should be
@@ -73,7 +85,7 @@ func (d *Docker) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOp
 		return nil, err
 	}
 
-	return docker.Builder(opts.Client, opts.Client.Host().UnixSocket("/var/run/docker.sock"), targz), nil
+	return docker.Builder(opts.Client, opts.Client.Host().UnixSocket("/var/run/docker.sock"), targz, d.Variant), nil
 }
 
 func (d *Docker) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
@@ -122,20 +134,29 @@ func (d *Docker) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDi
 // For example, the backend for `linux/amd64` and `linux/arm64` should not both produce a `bin` folder, they should produce a
 // `bin/linux-amd64` folder and a `bin/linux-arm64` folder. Callers can mount this as `bin` or whatever if they want.
 func (d *Docker) Filename(ctx context.Context) (string, error) {
-	ext := "docker.tar.gz"
-	if d.Ubuntu {
-		ext = "ubuntu.docker.tar.gz"
+	return packages.FileName(pgoName(d.Name, d.PGOProfile), d.Version, d.BuildID, d.Distro, d.Variant.Ext)
+}
+
+// Resources declares the resources used by the docker image. Verifying runs e2e tests in a browser, which counts twice.
+func (d *Docker) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if c, _ := backend.DistroCapabilities(d.Distro); action == "verify" && !c.Verify {
+		return nil
 	}
 
-	return packages.FileName(d.Name, d.Version, d.BuildID, d.Distro, ext)
+	return packageResources(d.Distro, action)
+}
+
+// ScanFile scans the image tarball for vulnerabilities in its OS packages and binaries.
+func (d *Docker) ScanFile(ctx context.Context, client *dagger.Client, file *dagger.File, opts *scan.Opts) (*scan.Report, error) {
+	return scan.Image(ctx, client, file, opts)
 }
 
 func (d *Docker) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
-	// Currently verifying riscv64 is unsupported (because alpine and ubuntu don't have riscv64 images yet)
-	if _, arch := backend.OSAndArch(d.Distro); arch == "riscv64" {
+	// Verification is skipped for the distributions that can't run in a container, like riscv64.
+	if c, _ := backend.DistroCapabilities(d.Distro); !c.Verify {
 		return nil
 	}
-	return docker.Verify(ctx, client, file, d.Src, d.YarnCache, d.Distro)
+	return docker.Verify(ctx, client, file, d.Src, d.YarnCache, d.Distro, d.Enterprise, d.Variant.Expectations)
 }
 
 func (d *Docker) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
@@ -143,35 +164,44 @@ func (d *Docker) VerifyDirectory(ctx context.Context, client *dagger.Client, dir
 }
 
 func NewDockerFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
-	tarball, err := NewTarballFromString(ctx, log, artifact, state)
+	options, err := pipeline.ParseFlags(artifact, DockerFlags)
 	if err != nil {
 		return nil, err
 	}
+	if err := rejectInstrumentation(artifact, options); err != nil {
+		return nil, err
+	}
 
-	options, err := pipeline.ParseFlags(artifact, DockerFlags)
+	tarball, err := NewTarballFromString(ctx, log, artifact, state)
 	if err != nil {
 		return nil, err
 	}
-
-	p, err := GetPackageDetails(ctx, options, state)
+	pgo, err := BackendPGOProfile(ctx, state, options)
 	if err != nil {
 		return nil, err
 	}
 
-	ubuntu, err := options.Bool(flags.Ubuntu)
+	p, err := GetPackageDetails(ctx, options, state)
 	if err != nil {
 		return nil, err
 	}
+	if p.Capabilities.DockerPlatform == "" {
+		return nil, fmt.Errorf("%s: docker images can't be built for '%s': %w", artifact, p.Distribution, backend.ErrorUnsupportedDistribution)
+	}
 
-	// Ubuntu Version to use as the base for the Grafana docker image (if this is a ubuntu artifact)
-	// This shouldn't fail if it's not set by the user, instead it'll default to 22.04 or something.
-	ubuntuImage, err := state.String(ctx, arguments.UbuntuImage)
+	baseName := docker.Variants[0].Name
+	if v, err := options.String(flags.DockerBase); err == nil {
+		baseName = v
+	}
+
+	variant, err := docker.VariantByName(baseName)
 	if err != nil {
 		return nil, err
 	}
+	variantArgs := arguments.DockerVariants[variant.Name]
 
-	// Same for Alpine
-	alpineImage, err := state.String(ctx, arguments.AlpineImage)
+	// The base image for the variant. This shouldn't fail if it's not set by the user, instead it'll default to 'alpine:latest', 'ubuntu:latest', etc.
+	base, err := state.String(ctx, variantArgs.Image)
 	if err != nil {
 		return nil, err
 	}
@@ -191,11 +221,7 @@ func NewDockerFromString(ctx context.Context, log *slog.Logger, artifact string,
 		return nil, err
 	}
 
-	format, err := state.String(ctx, arguments.TagFormat)
-	if err != nil {
-		return nil, err
-	}
-	ubuntuFormat, err := state.String(ctx, arguments.UbuntuTagFormat)
+	format, err := state.String(ctx, variantArgs.TagFormat)
 	if err != nil {
 		return nil, err
 	}
@@ -204,12 +230,6 @@ func NewDockerFromString(ctx context.Context, log *slog.Logger, artifact string,
 		return nil, err
 	}
 
-	base := alpineImage
-	if ubuntu {
-		format = ubuntuFormat
-		base = ubuntuImage
-	}
-
 	if p.Name == packages.PackageEnterpriseBoring {
 		format = boringFormat
 	}
@@ -224,7 +244,7 @@ func NewDockerFromString(ctx context.Context, log *slog.Logger, artifact string,
 		return nil, err
 	}
 
-	log.Info("initializing Docker artifact", "Org", org, "registry", registry, "repos", repos, "tag", format)
+	log.Info("initializing Docker artifact", "Org", org, "registry", registry, "repos", repos, "tag", format, "base", base)
 
 	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
 		ArtifactString: artifact,
@@ -235,8 +255,9 @@ func NewDockerFromString(ctx context.Context, log *slog.Logger, artifact string,
 			Distro:     p.Distribution,
 			Enterprise: p.Enterprise,
 			Tarball:    tarball,
+			PGOProfile: pgo,
 
-			Ubuntu:       ubuntu,
+			Variant:      variant,
 			BaseImage:    base,
 			Registry:     registry,
 			Org:          org,
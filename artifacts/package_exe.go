@@ -31,6 +31,8 @@ type Exe struct {
 	Enterprise   bool
 
 	Tarball *pipeline.Artifact
+	// PGOProfile is the profile that the backend in the tarball is optimized with, if any. It is part of the filename, like the tarball's.
+	PGOProfile *backend.PGOProfile
 }
 
 func (d *Exe) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
@@ -76,7 +78,16 @@ func (d *Exe) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOp
 // For example, the backend for `linux/amd64` and `linux/arm64` should not both produce a `bin` folder, they should produce a
 // `bin/linux-amd64` folder and a `bin/linux-arm64` folder. Callers can mount this as `bin` or whatever if they want.
 func (d *Exe) Filename(ctx context.Context) (string, error) {
-	return packages.FileName(d.Name, d.Version, d.BuildID, d.Distribution, "exe")
+	return packages.FileName(pgoName(d.Name, d.PGOProfile), d.Version, d.BuildID, d.Distribution, "exe")
+}
+
+// Resources declares the resources used by the exe. There is nothing to verify.
+func (d *Exe) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action == "verify" {
+		return nil
+	}
+
+	return packageResources(d.Distribution, action)
 }
 
 func (d *Exe) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
@@ -88,11 +99,18 @@ func (d *Exe) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *d
 }
 
 func NewExeFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, ExeFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectInstrumentation(artifact, options); err != nil {
+		return nil, err
+	}
 	tarball, err := NewTarballFromString(ctx, log, artifact, state)
 	if err != nil {
 		return nil, err
 	}
-	options, err := pipeline.ParseFlags(artifact, ExeFlags)
+	pgo, err := BackendPGOProfile(ctx, state, options)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +132,7 @@ func NewExeFromString(ctx context.Context, log *slog.Logger, artifact string, st
 			Distribution: p.Distribution,
 			Enterprise:   p.Enterprise,
 			Tarball:      tarball,
+			PGOProfile:   pgo,
 		},
 		Type:  pipeline.ArtifactTypeFile,
 		Flags: TargzFlags,
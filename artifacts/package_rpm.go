@@ -57,6 +57,8 @@ type RPM struct {
 	YarnCache *dagger.CacheVolume
 
 	Tarball *pipeline.Artifact
+	// PGOProfile is the profile that the backend in the tarball is optimized with, if any. It is part of the filename, like the tarball's.
+	PGOProfile *backend.PGOProfile
 }
 
 func (d *RPM) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
@@ -138,10 +140,23 @@ func (d *RPM) Filename(ctx context.Context) (string, error) {
 		name = packages.Name(d.NameOverride)
 	}
 
-	return packages.FileName(name, d.Version, d.BuildID, d.Distribution, "rpm")
+	return packages.FileName(pgoName(name, d.PGOProfile), d.Version, d.BuildID, d.Distribution, "rpm")
+}
+
+// Resources declares the resources used by the rpm. Verifying runs e2e tests in a browser, which counts twice.
+func (d *RPM) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if c, _ := backend.DistroCapabilities(d.Distribution); action == "verify" && !c.Verify {
+		return nil
+	}
+
+	return packageResources(d.Distribution, action)
 }
 
 func (d *RPM) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Verification is skipped for the distributions that don't have a ubi image to install the package in, like riscv64.
+	if c, _ := backend.DistroCapabilities(d.Distribution); !c.Verify {
+		return nil
+	}
 	return fpm.VerifyRpm(ctx, client, file, d.Src, d.YarnCache, d.Distribution, d.Enterprise, d.Sign, d.GPGPublicKey, d.GPGPrivateKey, d.GPGPassphrase)
 }
 
@@ -150,11 +165,18 @@ func (d *RPM) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *d
 }
 
 func NewRPMFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, RPMFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectInstrumentation(artifact, options); err != nil {
+		return nil, err
+	}
 	tarball, err := NewTarballFromString(ctx, log, artifact, state)
 	if err != nil {
 		return nil, err
 	}
-	options, err := pipeline.ParseFlags(artifact, RPMFlags)
+	pgo, err := BackendPGOProfile(ctx, state, options)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +184,9 @@ func NewRPMFromString(ctx context.Context, log *slog.Logger, artifact string, st
 	if err != nil {
 		return nil, err
 	}
+	if p.Capabilities.RPMArch == "" {
+		return nil, fmt.Errorf("%s: rpm packages can't be built for '%s': %w", artifact, p.Distribution, backend.ErrorUnsupportedDistribution)
+	}
 	sign, err := options.Bool(flags.Sign)
 	if err != nil {
 		return nil, err
@@ -223,6 +248,7 @@ func NewRPMFromString(ctx context.Context, log *slog.Logger, artifact string, st
 			Distribution:  p.Distribution,
 			Enterprise:    p.Enterprise,
 			Tarball:       tarball,
+			PGOProfile:    pgo,
 			Sign:          sign,
 			Src:           src,
 			YarnCache:     yarnCache,
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana-build/frontend"
 	"github.com/grafana/grafana-build/packages"
 	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/scan"
 	"github.com/grafana/grafana-build/targz"
 )
 
@@ -29,10 +30,19 @@ var (
 		// The go version used to build the backend
 		arguments.GoVersion,
 		arguments.ViceroyVersion,
+		arguments.BackendBinaries,
+		arguments.GoModCache,
+		arguments.GoBuildCache,
+		arguments.PGOProfile,
 		arguments.YarnCacheDirectory,
 	}
 	TargzFlags = flags.JoinFlags(
 		flags.StdPackageFlags(),
+		[]pipeline.Flag{
+			flags.PGOFlag,
+			flags.RaceFlag,
+			flags.CoverFlag,
+		},
 	)
 )
 
@@ -49,6 +59,11 @@ type Tarball struct {
 	GoVersion    string
 	Enterprise   bool
 
+	// Instrumentation is the instrumentation of the backend. Instrumented tarballs have it in their name, like 'grafana-race_...'.
+	Instrumentation backend.Instrumentation
+	// PGOProfile is the profile that the backend is optimized with, if any. The tarball has its checksum in the name, like 'grafana-pgo-0123456789ab_...'.
+	PGOProfile *backend.PGOProfile
+
 	Grafana   *dagger.Directory
 	YarnCache *dagger.CacheVolume
 
@@ -111,7 +126,20 @@ func NewTarballFromString(ctx context.Context, log *slog.Logger, artifact string
 	if err != nil {
 		return nil, err
 	}
-	return NewTarball(ctx, log, artifact, p.Distribution, p.Enterprise, p.Name, p.Version, p.BuildID, src, yarnCache, static, wireTag, tags, goVersion, viceroyVersion, experiments)
+	binaries, err := BackendBinaries(ctx, state, p.Name, p.Version)
+	if err != nil {
+		return nil, err
+	}
+	pgo, err := BackendPGOProfile(ctx, state, options)
+	if err != nil {
+		return nil, err
+	}
+	instrumentation, err := BackendInstrumentation(artifact, options, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTarball(ctx, log, artifact, p.Distribution, p.Enterprise, p.Name, p.Version, p.BuildID, src, yarnCache, static, wireTag, tags, goVersion, viceroyVersion, experiments, binaries, pgo, instrumentation)
 }
 
 // NewTarball returns a properly initialized Tarball artifact.
@@ -133,19 +161,25 @@ func NewTarball(
 	goVersion string,
 	viceroyVersion string,
 	experiments []string,
+	binaries []backend.Binary,
+	pgo *backend.PGOProfile,
+	instrumentation backend.Instrumentation,
 ) (*pipeline.Artifact, error) {
 	backendArtifact, err := NewBackend(ctx, log, artifact, &NewBackendOpts{
-		Name:           name,
-		Version:        version,
-		Distribution:   distro,
-		Src:            src,
-		Static:         static,
-		WireTag:        wireTag,
-		Tags:           tags,
-		GoVersion:      goVersion,
-		ViceroyVersion: viceroyVersion,
-		Experiments:    experiments,
-		Enterprise:     enterprise,
+		Name:            name,
+		Version:         version,
+		Distribution:    distro,
+		Src:             src,
+		Static:          static,
+		WireTag:         wireTag,
+		Tags:            tags,
+		GoVersion:       goVersion,
+		ViceroyVersion:  viceroyVersion,
+		Experiments:     experiments,
+		Enterprise:      enterprise,
+		Binaries:        binaries,
+		PGOProfile:      pgo,
+		Instrumentation: instrumentation,
 	})
 	if err != nil {
 		return nil, err
@@ -170,14 +204,16 @@ func NewTarball(
 		return nil, err
 	}
 	tarball := &Tarball{
-		Name:         name,
-		Distribution: distro,
-		Version:      version,
-		GoVersion:    goVersion,
-		BuildID:      buildID,
-		Grafana:      src,
-		Enterprise:   enterprise,
-		YarnCache:    cache,
+		Name:            name,
+		Distribution:    distro,
+		Version:         version,
+		GoVersion:       goVersion,
+		BuildID:         buildID,
+		Grafana:         src,
+		Enterprise:      enterprise,
+		YarnCache:       cache,
+		Instrumentation: instrumentation,
+		PGOProfile:      pgo,
 
 		Backend:        backendArtifact,
 		Frontend:       frontendArtifact,
@@ -298,16 +334,19 @@ func (t *Tarball) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishD
 }
 
 func (t *Tarball) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
-	// Currently verifying riscv64 is unsupported (because alpine and ubuntu don't have riscv64 images yet)
-	// windows/darwin verification may never be supported.
-	os, arch := backend.OSAndArch(t.Distribution)
-	if os != "linux" || arch == "riscv64" {
+	// Verification is skipped for the distributions that can't run in a container, like riscv64 or windows/darwin.
+	if c, _ := backend.DistroCapabilities(t.Distribution); !c.Verify {
 		return nil
 	}
 
 	return verifyTarball(ctx, client, file, t.Grafana, t.YarnCache, t.Distribution, t.Enterprise)
 }
 
+// ScanFile scans the Go binaries in the tarball for vulnerabilities using their embedded module information.
+func (t *Tarball) ScanFile(ctx context.Context, client *dagger.Client, file *dagger.File, opts *scan.Opts) (*scan.Report, error) {
+	return scan.Rootfs(ctx, client, containers.ExtractedArchive(client, file).Directory("bin"), opts)
+}
+
 func (t *Tarball) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
 	panic("not implemented") // TODO: Implement
 }
@@ -323,7 +362,22 @@ func (t *Tarball) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error
 }
 
 func (t *Tarball) Filename(ctx context.Context) (string, error) {
-	return packages.FileName(t.Name, t.Version, t.BuildID, t.Distribution, "tar.gz")
+	name := t.Name
+	if s := t.Instrumentation.Suffix(); s != "" {
+		name = packages.Name(fmt.Sprintf("%s-%s", name, s))
+	}
+
+	return packages.FileName(pgoName(name, t.PGOProfile), t.Version, t.BuildID, t.Distribution, "tar.gz")
+}
+
+// Resources declares the resources used by the tarball and the packages that are created from it.
+// Verifying runs e2e tests in a browser, which counts twice.
+func (t *Tarball) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if c, _ := backend.DistroCapabilities(t.Distribution); action == "verify" && !c.Verify {
+		return nil
+	}
+
+	return packageResources(t.Distribution, action)
 }
 
 func verifyTarball(
@@ -0,0 +1,39 @@
+package artifacts_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+func TestPGOFilename(t *testing.T) {
+	var (
+		profile = &backend.PGOProfile{Sha256: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+		other   = &backend.PGOProfile{Sha256: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"}
+	)
+
+	handlers := map[string]pipeline.ArtifactHandler{
+		"grafana_10.2.0_abc123_linux_amd64.tar.gz":                       &artifacts.Tarball{Name: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64},
+		"grafana-pgo-0123456789ab_10.2.0_abc123_linux_amd64.tar.gz":      &artifacts.Tarball{Name: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64, PGOProfile: profile},
+		"grafana-pgo-fedcba987654_10.2.0_abc123_linux_amd64.tar.gz":      &artifacts.Tarball{Name: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64, PGOProfile: other},
+		"grafana-race-pgo-0123456789ab_10.2.0_abc123_linux_amd64.tar.gz": &artifacts.Tarball{Name: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64, PGOProfile: profile, Instrumentation: backend.Instrumentation{Race: true}},
+		"grafana_10.2.0_abc123_linux_amd64.deb":                          &artifacts.Deb{Name: "grafana", NameOverride: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64},
+		"grafana-pgo-0123456789ab_10.2.0_abc123_linux_amd64.deb":         &artifacts.Deb{Name: "grafana", NameOverride: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64, PGOProfile: profile},
+		"grafana-pgo-0123456789ab_10.2.0_abc123_linux_amd64.zip":         &artifacts.Zip{Name: "grafana", Version: "10.2.0", BuildID: "abc123", Distribution: backend.DistLinuxAMD64, PGOProfile: profile},
+	}
+
+	for expect, h := range handlers {
+		t.Run(expect, func(t *testing.T) {
+			name, err := h.Filename(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != expect {
+				t.Errorf("expected filename '%s' but got '%s'", expect, name)
+			}
+		})
+	}
+}
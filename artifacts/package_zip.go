@@ -30,6 +30,8 @@ type Zip struct {
 	Enterprise   bool
 
 	Tarball *pipeline.Artifact
+	// PGOProfile is the profile that the backend in the tarball is optimized with, if any. It is part of the filename, like the tarball's.
+	PGOProfile *backend.PGOProfile
 }
 
 func (d *Zip) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
@@ -81,15 +83,31 @@ func (d *Zip) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *d
 // For example, the backend for `linux/amd64` and `linux/arm64` should not both produce a `bin` folder, they should produce a
 // `bin/linux-amd64` folder and a `bin/linux-arm64` folder. Callers can mount this as `bin` or whatever if they want.
 func (d *Zip) Filename(ctx context.Context) (string, error) {
-	return packages.FileName(d.Name, d.Version, d.BuildID, d.Distribution, "zip")
+	return packages.FileName(pgoName(d.Name, d.PGOProfile), d.Version, d.BuildID, d.Distribution, "zip")
+}
+
+// Resources declares the resources used by the zip. There is nothing to verify.
+func (d *Zip) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action == "verify" {
+		return nil
+	}
+
+	return packageResources(d.Distribution, action)
 }
 
 func NewZipFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	options, err := pipeline.ParseFlags(artifact, ZipFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectInstrumentation(artifact, options); err != nil {
+		return nil, err
+	}
 	tarball, err := NewTarballFromString(ctx, log, artifact, state)
 	if err != nil {
 		return nil, err
 	}
-	options, err := pipeline.ParseFlags(artifact, ZipFlags)
+	pgo, err := BackendPGOProfile(ctx, state, options)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +124,7 @@ func NewZipFromString(ctx context.Context, log *slog.Logger, artifact string, st
 			Distribution: p.Distribution,
 			Enterprise:   p.Enterprise,
 			Tarball:      tarball,
+			PGOProfile:   pgo,
 		},
 		Type:  pipeline.ArtifactTypeFile,
 		Flags: TargzFlags,
@@ -2,6 +2,8 @@ package artifacts
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana-build/arguments"
 	"github.com/grafana/grafana-build/backend"
@@ -16,10 +18,15 @@ type PackageDetails struct {
 	Version      string
 	BuildID      string
 	Distribution backend.Distribution
+	Capabilities backend.Capabilities
 }
 
 func GetPackageDetails(ctx context.Context, options *pipeline.OptionsHandler, state pipeline.StateHandler) (PackageDetails, error) {
 	distro, err := options.String(flags.Distribution)
+	if err != nil {
+		return PackageDetails{}, fmt.Errorf("%w: %w", backend.ErrorUnsupportedDistribution, err)
+	}
+	capabilities, err := backend.DistroCapabilities(backend.Distribution(distro))
 	if err != nil {
 		return PackageDetails{}, err
 	}
@@ -47,6 +54,63 @@ func GetPackageDetails(ctx context.Context, options *pipeline.OptionsHandler, st
 		Version:      version,
 		BuildID:      buildID,
 		Distribution: backend.Distribution(distro),
+		Capabilities: capabilities,
 		Enterprise:   enterprise,
 	}, nil
 }
+
+var ErrorInstrumentedPackage = errors.New("race and cover builds are only supported for the backend and targz artifacts")
+
+// rejectInstrumentation returns ErrorInstrumentedPackage if the artifact has the 'race' or 'cover' flag.
+// Packages that are made from a tarball (deb, rpm, docker, zip, exe) are release artifacts and are never instrumented.
+func rejectInstrumentation(artifact string, options *pipeline.OptionsHandler) error {
+	race, err := options.Bool(flags.Race)
+	if err != nil {
+		return err
+	}
+	cover, err := options.Bool(flags.Cover)
+	if err != nil {
+		return err
+	}
+	if race || cover {
+		return fmt.Errorf("%s: %w", artifact, ErrorInstrumentedPackage)
+	}
+
+	return nil
+}
+
+// pgoName returns the name of a package whose backend is optimized with the profile, like 'grafana-pgo-0123456789ab',
+// so that it isn't mixed up with the same package built with another profile or without one. If pgo is nil, then name is returned.
+func pgoName(name packages.Name, pgo *backend.PGOProfile) packages.Name {
+	if pgo == nil {
+		return name
+	}
+
+	return packages.Name(fmt.Sprintf("%s-%s", name, pgo.Suffix()))
+}
+
+// backendWeight returns the weight of compiling the backend for the distro. Cross-compiling in a viceroy container is slower and counts twice.
+func backendWeight(distro backend.Distribution) int64 {
+	if backend.UsesViceroy(distro) {
+		return 2
+	}
+	return 1
+}
+
+// packageResources returns the resources used by a package of the backend and the frontend, like a tar.gz or a deb.
+// Exporting a package builds its dependencies, so it uses both the backend and the frontend resources.
+func packageResources(distro backend.Distribution, action string) []pipeline.Resource {
+	switch action {
+	case "export":
+		return []pipeline.Resource{
+			{Class: pipeline.ResourceBackend, Weight: backendWeight(distro)},
+			{Class: pipeline.ResourceFrontend, Weight: 1},
+		}
+	case "verify":
+		return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 2}}
+	case "scan":
+		return []pipeline.Resource{{Class: pipeline.ResourceNetwork, Weight: 1}}
+	}
+
+	return nil
+}
@@ -77,6 +77,15 @@ func (f *BundledPlugins) Filename(ctx context.Context) (string, error) {
 	return path.Join("bin", "bundled-plugins"), nil
 }
 
+// Resources declares that building the bundled plugins is memory-heavy.
+func (f *BundledPlugins) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
 func NewBundledPlugins(ctx context.Context, log *slog.Logger, artifact string, src *dagger.Directory, version string, cacheVolume *dagger.CacheVolume) (*pipeline.Artifact, error) {
 	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
 		ArtifactString: artifact,
@@ -0,0 +1,41 @@
+package artifacts_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/scan"
+)
+
+type scannerHandler struct {
+	filenameHandler
+}
+
+func (h *scannerHandler) ScanFile(ctx context.Context, client *dagger.Client, file *dagger.File, opts *scan.Opts) (*scan.Report, error) {
+	return &scan.Report{}, nil
+}
+
+func TestArtifactCanScan(t *testing.T) {
+	cases := map[string]struct {
+		handler pipeline.ArtifactHandler
+		expect  bool
+	}{
+		"scanner":     {&scannerHandler{filenameHandler{filename: "grafana.tar.gz"}}, true},
+		"not scanner": {&filenameHandler{filename: "grafana.tar.gz"}, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			a, err := pipeline.ArtifactWithLogging(context.Background(), slog.Default(), &pipeline.Artifact{Handler: v.handler})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c := pipeline.ArtifactCanScan(a); c != v.expect {
+				t.Errorf("expected %t for the logged handler but got %t", v.expect, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package artifacts
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/grafana/grafana-build/cliutil"
+	"github.com/grafana/grafana-build/pipeline"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+)
+
+// A limit is a semaphore that remembers its size, so that weights larger than the size can be clamped instead of blocking forever.
+type limit struct {
+	sm   *semaphore.Weighted
+	size int64
+}
+
+func newLimit(size int64) *limit {
+	return &limit{
+		sm:   semaphore.NewWeighted(size),
+		size: size,
+	}
+}
+
+func (l *limit) weight(w int64) int64 {
+	if w < 1 {
+		return 1
+	}
+	if w > l.size {
+		return l.size
+	}
+	return w
+}
+
+// A Scheduler limits the actions that run at the same time.
+// Every action counts as 1 towards the '--parallel' limit. Actions of artifacts that implement pipeline.ArtifactResourcer
+// also count their weight towards the limit of each of their resource classes, like '--parallel-frontend'.
+type Scheduler struct {
+	parallel *limit
+	classes  map[pipeline.ResourceClass]*limit
+}
+
+// NewScheduler creates a Scheduler that runs up to parallel actions at the same time.
+// Classes sets the limit of each resource class; classes that are not set, or are set to 0, are only limited by parallel.
+func NewScheduler(parallel int64, classes map[pipeline.ResourceClass]int64) *Scheduler {
+	s := &Scheduler{
+		parallel: newLimit(parallel),
+		classes:  map[pipeline.ResourceClass]*limit{},
+	}
+
+	for class, size := range classes {
+		if size > 0 {
+			s.classes[class] = newLimit(size)
+		}
+	}
+
+	return s
+}
+
+// SchedulerFromFlags creates a Scheduler using the '--parallel' and '--parallel-<class>' flags.
+func SchedulerFromFlags(c cliutil.CLIContext) *Scheduler {
+	classes := make(map[pipeline.ResourceClass]int64, len(pipeline.ResourceClasses))
+	for _, class := range pipeline.ResourceClasses {
+		classes[class] = c.Int64(pipeline.ParallelFlag(class))
+	}
+
+	return NewScheduler(c.Int64("parallel"), classes)
+}
+
+// Acquire blocks until the action can use the resources and records the time spent waiting.
+// Resources are always acquired in the order of pipeline.ResourceClasses so that two actions can't wait on each other.
+// The returned function must be called to release the resources when the action is done.
+func (s *Scheduler) Acquire(ctx context.Context, log *slog.Logger, action string, resources []pipeline.Resource) (func(), error) {
+	weights := map[pipeline.ResourceClass]int64{}
+	for _, r := range resources {
+		weights[r.Class] += r.Weight
+	}
+
+	log.Info("Acquiring resources", "resources", weights)
+	start := time.Now()
+
+	var acquired []func()
+	release := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			acquired[i]()
+		}
+	}
+
+	take := func(l *limit, w int64) error {
+		w = l.weight(w)
+		if err := l.sm.Acquire(ctx, w); err != nil {
+			return err
+		}
+		acquired = append(acquired, func() { l.sm.Release(w) })
+		return nil
+	}
+
+	if err := take(s.parallel, 1); err != nil {
+		log.Info("Error acquiring resources", "error", err)
+		return nil, err
+	}
+
+	for _, class := range pipeline.ResourceClasses {
+		l, ok := s.classes[class]
+		if !ok || weights[class] == 0 {
+			continue
+		}
+		if err := take(l, weights[class]); err != nil {
+			release()
+			log.Info("Error acquiring resources", "error", err)
+			return nil, err
+		}
+	}
+
+	wait := time.Since(start)
+	log.Info("Acquired resources", "wait", wait)
+
+	semaphoreWaitHistogram.Record(ctx, wait.Seconds(), metric.WithAttributes(attribute.String("action", action)))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Float64("semaphore.wait_seconds", wait.Seconds()))
+
+	return release, nil
+}
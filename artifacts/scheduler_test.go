@@ -0,0 +1,139 @@
+package artifacts_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func frontend(weight int64) []pipeline.Resource {
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: weight}}
+}
+
+func TestSchedulerAcquire(t *testing.T) {
+	type acquire struct {
+		resources []pipeline.Resource
+		blocks    bool
+	}
+
+	cases := map[string]struct {
+		parallel int64
+		classes  map[pipeline.ResourceClass]int64
+		acquire  []acquire
+	}{
+		"parallel limits every action": {
+			parallel: 2,
+			acquire: []acquire{
+				{resources: nil},
+				{resources: frontend(1)},
+				{resources: nil, blocks: true},
+			},
+		},
+		"class limit": {
+			parallel: 4,
+			classes:  map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 1},
+			acquire: []acquire{
+				{resources: frontend(1)},
+				{resources: []pipeline.Resource{{Class: pipeline.ResourceBackend, Weight: 1}}},
+				{resources: frontend(1), blocks: true},
+			},
+		},
+		"weights add up": {
+			parallel: 4,
+			classes:  map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 3},
+			acquire: []acquire{
+				{resources: frontend(2)},
+				{resources: frontend(2), blocks: true},
+			},
+		},
+		"weights larger than the limit are clamped": {
+			parallel: 4,
+			classes:  map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 1},
+			acquire: []acquire{
+				{resources: frontend(2)},
+				{resources: frontend(1), blocks: true},
+			},
+		},
+		"unlimited class": {
+			parallel: 3,
+			classes:  map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 0},
+			acquire: []acquire{
+				{resources: frontend(2)},
+				{resources: frontend(2)},
+				{resources: frontend(2)},
+			},
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			s := artifacts.NewScheduler(v.parallel, v.classes)
+			for i, a := range v.acquire {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				release, err := s.Acquire(ctx, discard, "export", a.resources)
+				if a.blocks {
+					if err == nil {
+						t.Fatalf("expected action %d to wait for resources", i)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("expected action %d to acquire resources but got %v", i, err)
+				}
+				defer release()
+			}
+		})
+	}
+}
+
+func TestSchedulerRelease(t *testing.T) {
+	ctx := context.Background()
+	s := artifacts.NewScheduler(4, map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 1})
+
+	release, err := s.Acquire(ctx, discard, "verify", frontend(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	release, err = s.Acquire(ctx, discard, "verify", frontend(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestSchedulerReleasesOnError(t *testing.T) {
+	s := artifacts.NewScheduler(2, map[pipeline.ResourceClass]int64{pipeline.ResourceFrontend: 1})
+
+	hold, err := s.Acquire(context.Background(), discard, "export", frontend(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hold()
+
+	// This action gets the last '--parallel' slot but has to wait for the frontend class.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, discard, "export", frontend(1)); err == nil {
+		t.Fatal("expected the action to wait for the frontend class")
+	}
+
+	// The action that gave up must not keep the '--parallel' slot.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release, err := s.Acquire(ctx, discard, "export", nil)
+	if err != nil {
+		t.Fatalf("expected the '--parallel' slot to be released but got %v", err)
+	}
+	release()
+}
@@ -82,6 +82,15 @@ func (f *Storybook) Filename(ctx context.Context) (string, error) {
 	return filepath.Join(f.Version, "storybook"), nil
 }
 
+// Resources declares that building the storybook is memory-heavy.
+func (f *Storybook) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
 func NewStorybookFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
 	grafanaDir, err := GrafanaDir(ctx, state, false)
 	if err != nil {
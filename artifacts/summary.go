@@ -0,0 +1,457 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/stringutil"
+)
+
+// A Status is the result of an action like 'verify', 'scan', or 'publish' in the summary.
+type Status string
+
+const (
+	StatusOK     Status = "ok"
+	StatusFailed Status = "failed"
+)
+
+// A SummaryEntry is a single artifact in the build summary, either requested with '-a' or built as a dependency of one.
+// The durations don't include the time spent waiting for the scheduler.
+type SummaryEntry struct {
+	Artifact string `json:"artifact"`
+	Filename string `json:"filename"`
+	// Dependency is true if the artifact was not requested with '-a', but was built because a requested artifact depends on it.
+	Dependency bool `json:"dependency,omitempty"`
+
+	// BuildDuration is the time spent adding the artifact and its dependencies to the dag. Most of the work runs lazily when the artifact is exported,
+	// so this is usually short, unless the builder needs a result right away, like reading the node version from the source.
+	BuildDuration float64 `json:"buildSeconds,omitempty"`
+	// ExportDuration includes building the artifact and its dependencies, unless they were cached by dagger.
+	ExportDuration  float64 `json:"exportSeconds,omitempty"`
+	VerifyDuration  float64 `json:"verifySeconds,omitempty"`
+	ScanDuration    float64 `json:"scanSeconds,omitempty"`
+	PublishDuration float64 `json:"publishSeconds,omitempty"`
+
+	// StoreMisses is the number of times that the artifact was not in the artifact store and was added to the dag.
+	// StoreHits is the number of times that it was taken from the store instead, because another artifact depends on it too.
+	StoreMisses int `json:"storeMisses,omitempty"`
+	StoreHits   int `json:"storeHits,omitempty"`
+
+	Size   int64  `json:"size,omitempty"`
+	Sha256 string `json:"sha256,omitempty"`
+
+	Verify  Status `json:"verify,omitempty"`
+	Scan    Status `json:"scan,omitempty"`
+	Publish Status `json:"publish,omitempty"`
+
+	// PreviousSize is the size of the artifact in the previous manifest, if there was one.
+	PreviousSize int64 `json:"previousSize,omitempty"`
+	// SizeRegression is true if the artifact grew more than the threshold compared to the previous manifest.
+	SizeRegression bool `json:"sizeRegression,omitempty"`
+}
+
+// Growth returns the size difference compared to the previous manifest as a percentage, or 0 if the previous size is not known.
+func (e *SummaryEntry) Growth() float64 {
+	if e.PreviousSize == 0 || e.Size == 0 {
+		return 0
+	}
+
+	return float64(e.Size-e.PreviousSize) / float64(e.PreviousSize) * 100
+}
+
+// A SummaryManifest is the JSON version of the summary. The manifest of a previous build can be compared to the current one with '--previous-manifest'.
+type SummaryManifest struct {
+	Artifacts []SummaryEntry `json:"artifacts"`
+}
+
+// ReadSummaryManifest reads a manifest that was written with '--summary-manifest'.
+func ReadSummaryManifest(path string) (*SummaryManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SummaryManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest '%s': %w", path, err)
+	}
+
+	return m, nil
+}
+
+// A Summary collects the durations, sizes, and results of every artifact in the build. It is safe to use from multiple goroutines.
+type Summary struct {
+	mu      sync.Mutex
+	entries []*SummaryEntry
+}
+
+type summaryKey struct{}
+
+// ContextWithSummary returns a context that records the actions on artifacts in s.
+func ContextWithSummary(ctx context.Context, s *Summary) context.Context {
+	return context.WithValue(ctx, summaryKey{}, s)
+}
+
+// SummaryFromContext returns the summary in the context, or nil if there isn't one.
+func SummaryFromContext(ctx context.Context) *Summary {
+	s, _ := ctx.Value(summaryKey{}).(*Summary)
+	return s
+}
+
+// entry returns the entry of the filename, creating it if it doesn't exist. The mutex must be locked.
+func (s *Summary) entry(artifact, filename string) *SummaryEntry {
+	for _, e := range s.entries {
+		if e.Filename == filename {
+			return e
+		}
+	}
+
+	e := &SummaryEntry{
+		Artifact:   artifact,
+		Filename:   filename,
+		Dependency: true,
+	}
+	s.entries = append(s.entries, e)
+	return e
+}
+
+// Add adds an artifact that was requested with '-a', so that it is in the summary even if none of its actions finish.
+// Artifacts that are not added, but have actions recorded, are dependencies.
+func (s *Summary) Add(ctx context.Context, a *pipeline.Artifact) {
+	filename, _ := a.Handler.Filename(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(a.ArtifactString, filename).Dependency = false
+}
+
+// Record records the result of an action on an artifact.
+// If the action is 'build' and cached is true, then the artifact was taken from the store instead of being built.
+// Exported paths are used to calculate the size and checksum of the artifact.
+func (s *Summary) Record(ctx context.Context, a *pipeline.Artifact, action string, d time.Duration, cached bool, paths []string, err error) {
+	if s == nil {
+		return
+	}
+	filename, _ := a.Handler.Filename(ctx)
+
+	status := StatusOK
+	if err != nil {
+		status = StatusFailed
+	}
+
+	// Hash the file before locking so that large files don't block other actions.
+	var (
+		size int64
+		sum  string
+	)
+	if path := exportedPath(filename, paths); path != "" {
+		size, _ = pathSize(path)
+		sum, _ = fileSha256(path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entry(a.ArtifactString, filename)
+	switch action {
+	case "build":
+		if cached {
+			e.StoreHits++
+			return
+		}
+		e.StoreMisses++
+		e.BuildDuration = d.Seconds()
+	case "export":
+		e.ExportDuration = d.Seconds()
+		e.Size = size
+		e.Sha256 = sum
+	case "verify":
+		e.VerifyDuration = d.Seconds()
+		e.Verify = status
+	case "scan":
+		e.ScanDuration = d.Seconds()
+		e.Scan = status
+	case "publish":
+		e.PublishDuration = d.Seconds()
+		e.Publish = status
+	}
+}
+
+// exportedPath returns the path of the artifact itself in the list of exported paths, which can also have a checksum file.
+func exportedPath(filename string, paths []string) string {
+	for _, p := range paths {
+		if filepath.Base(p) == filepath.Base(filename) {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// pathSize returns the size of the file, or the total size of the files in the directory.
+func pathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+
+	return size, err
+}
+
+// fileSha256 returns the sha256 checksum of the file. Directories don't have a checksum.
+func fileSha256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Entries returns a copy of the entries. Requested artifacts come first in the order that they were added, followed by the dependencies sorted by filename.
+func (s *Summary) Entries() []SummaryEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]SummaryEntry, len(s.entries))
+	for i, e := range s.entries {
+		entries[i] = *e
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Dependency != entries[j].Dependency {
+			return !entries[i].Dependency
+		}
+		if entries[i].Dependency {
+			return entries[i].Filename < entries[j].Filename
+		}
+		return false
+	})
+
+	return entries
+}
+
+// Compare flags the artifacts that grew more than threshold percent compared to the same artifact in the previous manifest.
+// Artifacts are matched by their filename, or by their artifact string if the filename changed, for example because the version is different.
+// It returns the entries with a size regression.
+func (s *Summary) Compare(previous *SummaryManifest, threshold float64) []SummaryEntry {
+	if previous == nil {
+		return nil
+	}
+
+	byFilename := map[string]SummaryEntry{}
+	byArtifact := map[string]SummaryEntry{}
+	for _, e := range previous.Artifacts {
+		byFilename[e.Filename] = e
+		if !e.Dependency {
+			byArtifact[e.Artifact] = e
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var regressions []SummaryEntry
+	for _, e := range s.entries {
+		p, ok := byFilename[e.Filename]
+		if !ok && !e.Dependency {
+			p, ok = byArtifact[e.Artifact]
+		}
+		if !ok || p.Size == 0 || e.Size == 0 {
+			continue
+		}
+
+		e.PreviousSize = p.Size
+		e.SizeRegression = e.Growth() > threshold
+		if e.SizeRegression {
+			regressions = append(regressions, *e)
+		}
+	}
+
+	return regressions
+}
+
+// Manifest returns the summary as a manifest that can be written as JSON.
+func (s *Summary) Manifest() *SummaryManifest {
+	return &SummaryManifest{
+		Artifacts: s.Entries(),
+	}
+}
+
+// WriteJSON writes the summary manifest as indented JSON.
+func (s *Summary) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s.Manifest())
+}
+
+var summaryColumns = []string{"ARTIFACT", "FILENAME", "BUILD", "EXPORT", "SIZE", "SHA256", "STORE", "VERIFY", "SCAN", "PUBLISH"}
+
+// row returns the columns of the entry as they are shown in the table and the markdown summary.
+func (e *SummaryEntry) row() []string {
+	artifact := e.Artifact
+	if e.Dependency {
+		artifact = "(dependency)"
+	}
+
+	sum := e.Sha256
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+
+	return []string{
+		artifact,
+		e.Filename,
+		formatDuration(e.BuildDuration),
+		formatDuration(e.ExportDuration),
+		e.formatSize(),
+		orDash(sum),
+		e.formatStore(),
+		formatStatus(e.Verify, e.VerifyDuration),
+		formatStatus(e.Scan, e.ScanDuration),
+		formatStatus(e.Publish, e.PublishDuration),
+	}
+}
+
+// formatStore shows how often the artifact was built and how often it was taken from the artifact store, like '1 miss, 2 hits'.
+func (e *SummaryEntry) formatStore() string {
+	var counts []string
+	if e.StoreMisses != 0 {
+		counts = append(counts, plural(e.StoreMisses, "miss", "misses"))
+	}
+	if e.StoreHits != 0 {
+		counts = append(counts, plural(e.StoreHits, "hit", "hits"))
+	}
+	if len(counts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// formatStatus shows the status of an action with its duration, like 'ok (1m2s)'.
+func formatStatus(s Status, seconds float64) string {
+	if s == "" {
+		return "-"
+	}
+	if seconds == 0 {
+		return string(s)
+	}
+
+	return fmt.Sprintf("%s (%s)", s, formatDuration(seconds))
+}
+
+func (e *SummaryEntry) formatSize() string {
+	if e.Size == 0 {
+		return "-"
+	}
+
+	size := stringutil.FormatSize(e.Size)
+	if e.PreviousSize == 0 {
+		return size
+	}
+
+	size = fmt.Sprintf("%s (%+.1f%%)", size, e.Growth())
+	if e.SizeRegression {
+		size += " REGRESSION"
+	}
+
+	return size
+}
+
+func formatDuration(seconds float64) string {
+	if seconds == 0 {
+		return "-"
+	}
+
+	return (time.Duration(seconds * float64(time.Second))).Round(100 * time.Millisecond).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// WriteTable writes the summary as a table, like the one that is shown on stderr at the end of the build.
+func (s *Summary) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(summaryColumns, "\t"))
+	for _, e := range s.Entries() {
+		fmt.Fprintln(tw, strings.Join(e.row(), "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// WriteMarkdown writes the summary as a Markdown table, for example for a CI job summary.
+func (s *Summary) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## Build summary\n\n")
+	b.WriteString("| " + strings.Join(summaryColumns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(summaryColumns)) + "\n")
+
+	var regressions []SummaryEntry
+	for _, e := range s.Entries() {
+		row := e.row()
+		for i, v := range row {
+			row[i] = strings.ReplaceAll(v, "|", "\\|")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if e.SizeRegression {
+			regressions = append(regressions, e)
+		}
+	}
+
+	if len(regressions) != 0 {
+		b.WriteString("\n**Size regressions:**\n\n")
+		for _, e := range regressions {
+			fmt.Fprintf(&b, "- `%s` grew %.1f%% from %s to %s\n", e.Filename, e.Growth(), stringutil.FormatSize(e.PreviousSize), stringutil.FormatSize(e.Size))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,176 @@
+package artifacts_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+func TestSummaryRecord(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		summary = &artifacts.Summary{}
+		targz   = &pipeline.Artifact{ArtifactString: "targz:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.tar.gz"}}
+		deb     = &pipeline.Artifact{ArtifactString: "deb:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.deb"}}
+		backend = &pipeline.Artifact{ArtifactString: "backend:grafana:linux/amd64", Handler: &filenameHandler{filename: "bin/linux-amd64"}}
+	)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "grafana.tar.gz")
+	if err := os.WriteFile(path, []byte("grafana"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary.Add(ctx, targz)
+	summary.Add(ctx, deb)
+	// The backend is a dependency of both packages, so the second time it is taken from the store.
+	summary.Record(ctx, backend, "build", time.Second, false, nil, nil)
+	summary.Record(ctx, targz, "build", 2*time.Second, false, nil, nil)
+	summary.Record(ctx, backend, "build", 0, true, nil, nil)
+	summary.Record(ctx, targz, "export", time.Minute, false, []string{path, path + ".sha256"}, nil)
+	summary.Record(ctx, targz, "verify", 30*time.Second, false, nil, nil)
+	summary.Record(ctx, targz, "publish", 5*time.Second, false, nil, nil)
+	summary.Record(ctx, deb, "verify", time.Minute, false, nil, errors.New("health check failed"))
+
+	entries := summary.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries but got %d", len(entries))
+	}
+
+	if e := entries[0]; e.Filename != "grafana.tar.gz" {
+		t.Errorf("expected the first entry to be the requested tarball but got %+v", e)
+	}
+	sum := sha256.Sum256([]byte("grafana"))
+	if e := entries[0]; e.Size != 7 || e.Sha256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected the size and checksum of the exported file but got %d '%s'", e.Size, e.Sha256)
+	}
+	if e := entries[0]; e.BuildDuration != 2 || e.ExportDuration != 60 || e.VerifyDuration != 30 || e.Verify != artifacts.StatusOK || e.Publish != artifacts.StatusOK {
+		t.Errorf("unexpected durations or status: %+v", e)
+	}
+	if e := entries[1]; e.Filename != "grafana.deb" || e.Verify != artifacts.StatusFailed || e.Dependency {
+		t.Errorf("expected the failed deb as the second entry but got %+v", e)
+	}
+	if e := entries[2]; e.Filename != "bin/linux-amd64" || !e.Dependency || e.BuildDuration != 1 || e.StoreMisses != 1 || e.StoreHits != 1 {
+		t.Errorf("expected the backend as a dependency that was taken from the store once but got %+v", e)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := summary.WriteTable(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"ok (30s)", "failed (1m0s)", "ok (5s)", "(dependency)", "1 miss, 1 hit"} {
+		if !strings.Contains(buf.String(), v) {
+			t.Errorf("expected '%s' in the table:\n%s", v, buf.String())
+		}
+	}
+}
+
+func TestSummaryCompare(t *testing.T) {
+	ctx := context.Background()
+	previous := &artifacts.SummaryManifest{
+		Artifacts: []artifacts.SummaryEntry{
+			{Artifact: "targz:grafana:linux/amd64", Filename: "grafana_10.1.0_linux_amd64.tar.gz", Size: 100},
+			{Artifact: "deb:grafana:linux/amd64", Filename: "grafana_10.2.0_amd64.deb", Size: 100},
+		},
+	}
+
+	cases := map[string]struct {
+		artifact   string
+		filename   string
+		size       int
+		previous   int64
+		regression bool
+	}{
+		"regression matched by artifact string": {
+			artifact:   "targz:grafana:linux/amd64",
+			filename:   "grafana_10.2.0_linux_amd64.tar.gz",
+			size:       120,
+			previous:   100,
+			regression: true,
+		},
+		"growth below the threshold": {
+			artifact: "deb:grafana:linux/amd64",
+			filename: "grafana_10.2.0_amd64.deb",
+			size:     105,
+			previous: 100,
+		},
+		"not in the previous manifest": {
+			artifact: "rpm:grafana:linux/amd64",
+			filename: "grafana_10.2.0_amd64.rpm",
+			size:     500,
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), v.filename)
+			if err := os.WriteFile(path, bytes.Repeat([]byte("a"), v.size), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			a := &pipeline.Artifact{ArtifactString: v.artifact, Handler: &filenameHandler{filename: v.filename}}
+			summary := &artifacts.Summary{}
+			summary.Add(ctx, a)
+			summary.Record(ctx, a, "export", time.Second, false, []string{path}, nil)
+
+			regressions := summary.Compare(previous, 10)
+			if (len(regressions) == 1) != v.regression {
+				t.Errorf("expected regression to be %t but got %v", v.regression, regressions)
+			}
+
+			e := summary.Entries()[0]
+			if e.PreviousSize != v.previous {
+				t.Errorf("expected previous size %d but got %d", v.previous, e.PreviousSize)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := summary.WriteMarkdown(buf); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(buf.String(), "Size regressions") != v.regression {
+				t.Errorf("unexpected markdown:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestReadSummaryManifest(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "grafana.tar.gz")
+	if err := os.WriteFile(path, []byte("grafana"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &pipeline.Artifact{ArtifactString: "targz:grafana:linux/amd64", Handler: &filenameHandler{filename: "grafana.tar.gz"}}
+	summary := &artifacts.Summary{}
+	summary.Add(ctx, a)
+	summary.Record(ctx, a, "export", time.Second, false, []string{path}, nil)
+
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	f, err := os.Create(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := summary.WriteJSON(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	m, err := artifacts.ReadSummaryManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Artifacts) != 1 || m.Artifacts[0].Size != 7 || m.Artifacts[0].Artifact != a.ArtifactString {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+}
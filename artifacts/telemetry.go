@@ -0,0 +1,170 @@
+package artifacts
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/grafana-build/events"
+	"github.com/grafana/grafana-build/flags"
+	"github.com/grafana/grafana-build/otel"
+	"github.com/grafana/grafana-build/pipeline"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/grafana/grafana-build/artifacts"
+
+var (
+	tracer = otel.Tracer(instrumentationName)
+	meter  = otel.Meter(instrumentationName)
+)
+
+var (
+	// durationHistogram records how long each action (build, export, verify, scan) took for an artifact.
+	durationHistogram, _ = meter.Float64Histogram("grafana_build.artifact.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time spent on an action for an artifact"),
+	)
+	// sizeHistogram records the size of the exported artifacts.
+	sizeHistogram, _ = meter.Int64Histogram("grafana_build.artifact.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of the exported artifact"),
+	)
+	// semaphoreWaitHistogram records how long an action waited for the scheduler to allow it to run.
+	semaphoreWaitHistogram, _ = meter.Float64Histogram("grafana_build.semaphore.wait",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time spent waiting for the scheduler before running an action"),
+	)
+)
+
+// ArtifactDistro returns the distribution in the artifact string, or an empty string if it doesn't have one.
+func ArtifactDistro(a *pipeline.Artifact) string {
+	options, err := pipeline.ParseFlags(a.ArtifactString, flags.DistroFlags())
+	if err != nil {
+		return ""
+	}
+
+	distro, _ := options.String(flags.Distribution)
+	return distro
+}
+
+// ArtifactAttributes returns the span and metric attributes that identify the artifact.
+// The distribution is only added if the artifact string has a distribution flag.
+func ArtifactAttributes(ctx context.Context, a *pipeline.Artifact) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("artifact", a.ArtifactString),
+	}
+
+	if filename, err := a.Handler.Filename(ctx); err == nil {
+		attrs = append(attrs, attribute.String("filename", filename))
+	}
+
+	if distro := ArtifactDistro(a); distro != "" {
+		attrs = append(attrs, attribute.String("distro", distro))
+	}
+
+	return attrs
+}
+
+// ArtifactEvent returns an event of type t with the metadata of the artifact.
+func ArtifactEvent(ctx context.Context, t events.Type, a *pipeline.Artifact) events.Event {
+	filename, _ := a.Handler.Filename(ctx)
+
+	return events.Event{
+		Type:     t,
+		Artifact: a.ArtifactString,
+		Filename: filename,
+		Distro:   ArtifactDistro(a),
+	}
+}
+
+// successEvents are the events that are emitted when an action finishes without an error.
+var successEvents = map[string]events.Type{
+	"build":   events.TypeBuilt,
+	"export":  events.TypeExported,
+	"verify":  events.TypeVerified,
+	"scan":    events.TypeScanned,
+	"publish": events.TypePublished,
+}
+
+// An actionTracker records the span, the duration metric, and the event of a single action on an artifact.
+type actionTracker struct {
+	Span trace.Span
+	// Event is emitted when the action ends. Callers can add details, like the exported paths, before calling End.
+	Event events.Event
+	// Skip prevents the success event from being emitted, for example when the artifact was already built.
+	Skip bool
+	// Cached is true if the artifact was taken from the artifact store instead of being built. It is shown in the summary.
+	Cached bool
+
+	action   string
+	artifact *pipeline.Artifact
+	start    time.Time
+}
+
+// startAction starts a span for the action on the artifact. End must be called when the action is done.
+func startAction(ctx context.Context, action string, a *pipeline.Artifact) (context.Context, *actionTracker) {
+	ctx, span := tracer.Start(ctx, "artifact."+action, trace.WithAttributes(ArtifactAttributes(ctx, a)...))
+
+	return ctx, &actionTracker{
+		Span:     span,
+		Event:    ArtifactEvent(ctx, successEvents[action], a),
+		action:   action,
+		artifact: a,
+		start:    time.Now(),
+	}
+}
+
+// Started sets the start of the action to now. Actions call it after the scheduler allows them to run, so that the recorded duration
+// is the time spent running the action. The time spent waiting is recorded in the 'grafana_build.semaphore.wait' metric instead.
+func (t *actionTracker) Started() {
+	t.start = time.Now()
+}
+
+// End ends the span, records the duration of the action in the metrics and the summary, and emits the event. If err is not nil, then an 'artifact-failed' event is emitted instead.
+func (t *actionTracker) End(ctx context.Context, err error) {
+	duration := time.Since(t.start)
+	if err != nil {
+		otel.RecordFailed(t.Span, err, t.action+" failed")
+	}
+	t.Span.End()
+
+	durationHistogram.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		attribute.String("action", t.action),
+		attribute.String("artifact", t.artifact.ArtifactString),
+		attribute.Bool("error", err != nil),
+	))
+
+	SummaryFromContext(ctx).Record(ctx, t.artifact, t.action, duration, t.Cached, t.Event.Paths, err)
+
+	e := t.Event
+	e.Duration = duration.Seconds()
+	if err != nil {
+		e.Type = events.TypeFailed
+		e.Action = t.action
+		events.Emit(e.WithError(err))
+		return
+	}
+	if !t.Skip {
+		events.Emit(e)
+	}
+}
+
+// recordSize records the size of the exported file, or the total size of the files in the exported directory.
+func recordSize(ctx context.Context, a *pipeline.Artifact, path string) {
+	size, err := pathSize(path)
+	if err != nil {
+		return
+	}
+
+	sizeHistogram.Record(ctx, size, metric.WithAttributes(
+		attribute.String("artifact", a.ArtifactString),
+		attribute.String("file", filepath.Base(path)),
+	))
+	trace.SpanFromContext(ctx).AddEvent("exported", trace.WithAttributes(
+		attribute.String("path", path),
+		attribute.Int64("size", size),
+	))
+}
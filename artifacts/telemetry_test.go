@@ -0,0 +1,61 @@
+package artifacts_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/pipeline"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type filenameHandler struct {
+	pipeline.ArtifactHandler
+	filename string
+}
+
+func (h *filenameHandler) Filename(ctx context.Context) (string, error) {
+	return h.filename, nil
+}
+
+func TestArtifactAttributes(t *testing.T) {
+	cases := map[string]struct {
+		artifact string
+		expect   map[attribute.Key]string
+	}{
+		"with distro": {
+			artifact: "targz:grafana:linux/arm64",
+			expect: map[attribute.Key]string{
+				"artifact": "targz:grafana:linux/arm64",
+				"filename": "grafana.tar.gz",
+				"distro":   "linux/arm64",
+			},
+		},
+		"without distro": {
+			artifact: "frontend:enterprise",
+			expect: map[attribute.Key]string{
+				"artifact": "frontend:enterprise",
+				"filename": "grafana.tar.gz",
+			},
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			a := &pipeline.Artifact{
+				ArtifactString: v.artifact,
+				Handler:        &filenameHandler{filename: "grafana.tar.gz"},
+			}
+
+			attrs := artifacts.ArtifactAttributes(context.Background(), a)
+			if len(attrs) != len(v.expect) {
+				t.Fatalf("expected %d attributes but got %v", len(v.expect), attrs)
+			}
+			for _, attr := range attrs {
+				if val := attr.Value.AsString(); val != v.expect[attr.Key] {
+					t.Errorf("expected attribute '%s' to be '%s' but got '%s'", attr.Key, v.expect[attr.Key], val)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/arguments"
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/flags"
+	"github.com/grafana/grafana-build/packages"
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/shards"
+)
+
+var (
+	BackendTestFlags     = flags.PackageNameFlags
+	BackendTestArguments = []pipeline.Argument{
+		arguments.GrafanaDirectory,
+		arguments.EnterpriseDirectory,
+		arguments.GoVersion,
+		arguments.ViceroyVersion,
+		arguments.GoModCache,
+		arguments.GoBuildCache,
+		arguments.GoTestPackages,
+		arguments.GoTestShard,
+	}
+)
+
+var BackendTestInitializer = Initializer{
+	InitializerFunc: NewBackendTestFromString,
+	Arguments:       BackendTestArguments,
+}
+
+var ErrorTestsFailed = errors.New("tests failed")
+
+// BackendTest runs 'go test' in the backend build container and produces a directory with the JUnit report, the coverage profile, and the test log.
+// The tests run on the distribution of the '--platform', because they're executed in the build container.
+type BackendTest struct {
+	Name           packages.Name
+	Src            *dagger.Directory
+	BuildOpts      *backend.BuildOpts
+	GoVersion      string
+	ViceroyVersion string
+
+	Packages []string
+	Shard    shards.Shard
+}
+
+func (b *BackendTest) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
+	return nil, nil
+}
+
+// Builder returns the same container that the backend of the platform's distribution is compiled in.
+func (b *BackendTest) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	distro := backend.Distribution(opts.Platform)
+	if _, err := backend.DistroCapabilities(distro); err != nil {
+		return nil, fmt.Errorf("backend tests can't run on platform '%s': %w", opts.Platform, err)
+	}
+	if backend.UsesViceroy(distro) {
+		return nil, fmt.Errorf("backend tests can't run on platform '%s': it's cross-compiled: %w", opts.Platform, backend.ErrorUnsupportedDistribution)
+	}
+
+	return (&Backend{
+		Name:           b.Name,
+		Src:            b.Src,
+		Distribution:   distro,
+		BuildOpts:      b.BuildOpts,
+		GoVersion:      b.GoVersion,
+		ViceroyVersion: b.ViceroyVersion,
+	}).Builder(ctx, opts)
+}
+
+func (b *BackendTest) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
+	// Not a file
+	return nil, nil
+}
+
+func (b *BackendTest) BuildDir(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.Directory, error) {
+	return backend.Test(builder, &backend.TestOpts{
+		Packages: b.Packages,
+		Tags:     b.BuildOpts.Tags,
+		Shard:    b.Shard,
+	}), nil
+}
+
+func (b *BackendTest) Publisher(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (b *BackendTest) PublishFile(ctx context.Context, opts *pipeline.ArtifactPublishFileOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (b *BackendTest) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (b *BackendTest) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Not a file
+	return nil
+}
+
+// AlwaysVerify fails the artifact when 'go test' fails, after the results are exported, even without '--verify'.
+func (b *BackendTest) AlwaysVerify() bool {
+	return true
+}
+
+// VerifyDirectory fails if 'go test' failed. The failures are in the 'test.log' and 'junit.xml' of the exported directory.
+func (b *BackendTest) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
+	return verifyExitCode(ctx, dir.File(backend.TestExitCodeFile), "go test")
+}
+
+// Filename should return a deterministic file or folder name that this build will produce.
+// The package patterns aren't in the name because they're the same for every backend-test artifact of a run.
+func (b *BackendTest) Filename(ctx context.Context) (string, error) {
+	return filepath.Join("backend-test", string(b.Name), b.Shard.Name()), nil
+}
+
+// Resources declares that running the backend tests is CPU-heavy.
+func (b *BackendTest) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceBackend, Weight: 1}}
+}
+
+// verifyExitCode returns ErrorTestsFailed if the file with the exit code of a test command doesn't have a 0 in it.
+func verifyExitCode(ctx context.Context, file *dagger.File, command string) error {
+	code, err := file.Contents(ctx)
+	if err != nil {
+		return err
+	}
+	if code = strings.TrimSpace(code); code != "0" {
+		return fmt.Errorf("%s exited with code %s: %w", command, code, ErrorTestsFailed)
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated list, like the package patterns of --go-test-packages.
+func splitList(s string) []string {
+	patterns := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			patterns = append(patterns, v)
+		}
+	}
+
+	return patterns
+}
+
+func NewBackendTestFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	goVersion, err := state.String(ctx, arguments.GoVersion)
+	if err != nil {
+		return nil, err
+	}
+	viceroyVersion, err := state.String(ctx, arguments.ViceroyVersion)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := state.String(ctx, arguments.GoTestPackages)
+	if err != nil {
+		return nil, err
+	}
+	s, err := state.String(ctx, arguments.GoTestShard)
+	if err != nil {
+		return nil, err
+	}
+	shard, err := shards.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := pipeline.ParseFlags(artifact, BackendTestFlags)
+	if err != nil {
+		return nil, err
+	}
+	name, err := options.String(flags.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	enterprise, err := options.Bool(flags.Enterprise)
+	if err != nil {
+		return nil, err
+	}
+	wireTag, err := options.String(flags.WireTag)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := options.StringSlice(flags.GoTags)
+	if err != nil {
+		return nil, err
+	}
+	experiments, err := options.StringSlice(flags.GoExperiments)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := GrafanaDir(ctx, state, enterprise)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Initializing backend test artifact with options", "name", name, "packages", patterns, "shard", shard)
+	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
+		ArtifactString: artifact,
+		Type:           pipeline.ArtifactTypeDirectory,
+		Flags:          BackendTestFlags,
+		Handler: &BackendTest{
+			Name: packages.Name(name),
+			Src:  src,
+			BuildOpts: &backend.BuildOpts{
+				Enterprise:        enterprise,
+				ExperimentalFlags: experiments,
+				WireTag:           wireTag,
+				Tags:              tags,
+			},
+			GoVersion:      goVersion,
+			ViceroyVersion: viceroyVersion,
+			Packages:       splitList(patterns),
+			Shard:          shard,
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package artifacts_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/grafana/grafana-build/artifacts"
+	frontendpkg "github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/pipeline"
+)
+
+func TestArtifactAlwaysVerify(t *testing.T) {
+	cases := map[string]struct {
+		handler pipeline.ArtifactHandler
+		expect  bool
+	}{
+		"backend test":                   {&artifacts.BackendTest{}, true},
+		"frontend test":                  {&artifacts.FrontendTest{}, true},
+		"frontend lint":                  {&artifacts.FrontendLint{}, true},
+		"coverage":                       {&artifacts.Coverage{Tarball: &pipeline.Artifact{Handler: &filenameHandler{filename: "grafana-cover.tar.gz"}}}, true},
+		"frontend stats with budgets":    {&artifacts.FrontendStats{Budgets: &frontendpkg.SizeBudgets{}}, true},
+		"frontend stats without budgets": {&artifacts.FrontendStats{}, false},
+		"package":                        {&filenameHandler{filename: "grafana.tar.gz"}, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			a, err := pipeline.ArtifactWithLogging(context.Background(), slog.Default(), &pipeline.Artifact{Handler: v.handler})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c := pipeline.ArtifactAlwaysVerify(a); c != v.expect {
+				t.Errorf("expected %t for the logged handler but got %t", v.expect, c)
+			}
+		})
+	}
+}
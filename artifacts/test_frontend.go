@@ -0,0 +1,270 @@
+package artifacts
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/arguments"
+	"github.com/grafana/grafana-build/flags"
+	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/packages"
+	"github.com/grafana/grafana-build/pipeline"
+	"github.com/grafana/grafana-build/shards"
+)
+
+var (
+	FrontendTestFlags     = flags.PackageNameFlags
+	FrontendTestArguments = []pipeline.Argument{
+		arguments.GrafanaDirectory,
+		arguments.EnterpriseDirectory,
+		arguments.YarnCacheDirectory,
+		arguments.FrontendTestShard,
+	}
+
+	FrontendLintFlags     = flags.PackageNameFlags
+	FrontendLintArguments = []pipeline.Argument{
+		arguments.GrafanaDirectory,
+		arguments.EnterpriseDirectory,
+		arguments.YarnCacheDirectory,
+		arguments.FrontendLintShard,
+		arguments.FrontendLintScripts,
+	}
+)
+
+var FrontendTestInitializer = Initializer{
+	InitializerFunc: NewFrontendTestFromString,
+	Arguments:       FrontendTestArguments,
+}
+
+var FrontendLintInitializer = Initializer{
+	InitializerFunc: NewFrontendLintFromString,
+	Arguments:       FrontendLintArguments,
+}
+
+// FrontendTest runs the jest tests in the frontend builder and produces a directory with the JUnit report, the coverage reports, and the test log.
+type FrontendTest struct {
+	Name      packages.Name
+	Src       *dagger.Directory
+	YarnCache *dagger.CacheVolume
+	Shard     shards.Shard
+}
+
+func (f *FrontendTest) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
+	return nil, nil
+}
+
+// Builder will return a node.js container that matches the .nvmrc in the Grafana source repository
+func (f *FrontendTest) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	return FrontendBuilder(ctx, f.Src, f.YarnCache, opts)
+}
+
+func (f *FrontendTest) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
+	// Not a file
+	return nil, nil
+}
+
+func (f *FrontendTest) BuildDir(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.Directory, error) {
+	return frontend.Test(builder, f.Shard), nil
+}
+
+func (f *FrontendTest) Publisher(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendTest) PublishFile(ctx context.Context, opts *pipeline.ArtifactPublishFileOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendTest) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendTest) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Not a file
+	return nil
+}
+
+// AlwaysVerify fails the artifact when jest fails, after the results are exported, even without '--verify'.
+func (f *FrontendTest) AlwaysVerify() bool {
+	return true
+}
+
+// VerifyDirectory fails if jest failed. The failures are in the 'test.log' and 'junit.xml' of the exported directory.
+func (f *FrontendTest) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
+	return verifyExitCode(ctx, dir.File(frontend.ExitCodeFile), "jest")
+}
+
+func (f *FrontendTest) Filename(ctx context.Context) (string, error) {
+	return filepath.Join("frontend-test", string(f.Name), f.Shard.Name()), nil
+}
+
+// Resources declares that running the jest tests is memory-heavy.
+func (f *FrontendTest) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
+// FrontendLint runs package.json scripts like 'typecheck' and 'lint' in the frontend builder and produces a directory with their logs and a JUnit report.
+// With a shard, the scripts are dealt to the shards in turn.
+type FrontendLint struct {
+	Name      packages.Name
+	Src       *dagger.Directory
+	YarnCache *dagger.CacheVolume
+	Scripts   []string
+	Shard     shards.Shard
+}
+
+func (f *FrontendLint) Dependencies(ctx context.Context) ([]*pipeline.Artifact, error) {
+	return nil, nil
+}
+
+// Builder will return a node.js container that matches the .nvmrc in the Grafana source repository
+func (f *FrontendLint) Builder(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	return FrontendBuilder(ctx, f.Src, f.YarnCache, opts)
+}
+
+func (f *FrontendLint) BuildFile(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.File, error) {
+	// Not a file
+	return nil, nil
+}
+
+func (f *FrontendLint) BuildDir(ctx context.Context, builder *dagger.Container, opts *pipeline.ArtifactContainerOpts) (*dagger.Directory, error) {
+	return frontend.Lint(builder, shards.Select(f.Scripts, f.Shard)), nil
+}
+
+func (f *FrontendLint) Publisher(ctx context.Context, opts *pipeline.ArtifactContainerOpts) (*dagger.Container, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendLint) PublishFile(ctx context.Context, opts *pipeline.ArtifactPublishFileOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendLint) PublisDir(ctx context.Context, opts *pipeline.ArtifactPublishDirOpts) error {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *FrontendLint) VerifyFile(ctx context.Context, client *dagger.Client, file *dagger.File) error {
+	// Not a file
+	return nil
+}
+
+// AlwaysVerify fails the artifact when a lint script fails, after the results are exported, even without '--verify'.
+func (f *FrontendLint) AlwaysVerify() bool {
+	return true
+}
+
+// VerifyDirectory fails if a lint script failed. The failures are in the '<script>.log' files and the 'junit.xml' of the exported directory.
+func (f *FrontendLint) VerifyDirectory(ctx context.Context, client *dagger.Client, dir *dagger.Directory) error {
+	return verifyExitCode(ctx, dir.File(frontend.ExitCodeFile), "a lint script")
+}
+
+func (f *FrontendLint) Filename(ctx context.Context) (string, error) {
+	return filepath.Join("frontend-lint", string(f.Name), f.Shard.Name()), nil
+}
+
+// Resources declares that type checking and linting is memory-heavy.
+func (f *FrontendLint) Resources(ctx context.Context, action string) []pipeline.Resource {
+	if action != "export" {
+		return nil
+	}
+
+	return []pipeline.Resource{{Class: pipeline.ResourceFrontend, Weight: 1}}
+}
+
+// frontendTestOpts are the options that the frontend-test and frontend-lint artifacts have in common.
+type frontendTestOpts struct {
+	Name      packages.Name
+	Src       *dagger.Directory
+	YarnCache *dagger.CacheVolume
+	Shard     shards.Shard
+}
+
+// frontendTestOptsFromString reads the options of the artifact string, and the shard from the shard argument of the artifact.
+func frontendTestOptsFromString(ctx context.Context, artifact string, state pipeline.StateHandler, shardArg pipeline.Argument) (*frontendTestOpts, error) {
+	options, err := pipeline.ParseFlags(artifact, FrontendTestFlags)
+	if err != nil {
+		return nil, err
+	}
+	name, err := options.String(flags.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	enterprise, err := options.Bool(flags.Enterprise)
+	if err != nil {
+		return nil, err
+	}
+	src, err := GrafanaDir(ctx, state, enterprise)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := state.CacheVolume(ctx, arguments.YarnCacheDirectory)
+	if err != nil {
+		return nil, err
+	}
+	s, err := state.String(ctx, shardArg)
+	if err != nil {
+		return nil, err
+	}
+	shard, err := shards.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &frontendTestOpts{
+		Name:      packages.Name(name),
+		Src:       src,
+		YarnCache: cache,
+		Shard:     shard,
+	}, nil
+}
+
+func NewFrontendTestFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	opts, err := frontendTestOptsFromString(ctx, artifact, state, arguments.FrontendTestShard)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Initializing frontend test artifact with options", "name", opts.Name, "shard", opts.Shard)
+	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
+		ArtifactString: artifact,
+		Type:           pipeline.ArtifactTypeDirectory,
+		Flags:          FrontendTestFlags,
+		Handler: &FrontendTest{
+			Name:      opts.Name,
+			Src:       opts.Src,
+			YarnCache: opts.YarnCache,
+			Shard:     opts.Shard,
+		},
+	})
+}
+
+func NewFrontendLintFromString(ctx context.Context, log *slog.Logger, artifact string, state pipeline.StateHandler) (*pipeline.Artifact, error) {
+	opts, err := frontendTestOptsFromString(ctx, artifact, state, arguments.FrontendLintShard)
+	if err != nil {
+		return nil, err
+	}
+	scripts, err := state.String(ctx, arguments.FrontendLintScripts)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Initializing frontend lint artifact with options", "name", opts.Name, "scripts", scripts, "shard", opts.Shard)
+	return pipeline.ArtifactWithLogging(ctx, log, &pipeline.Artifact{
+		ArtifactString: artifact,
+		Type:           pipeline.ArtifactTypeDirectory,
+		Flags:          FrontendLintFlags,
+		Handler: &FrontendLint{
+			Name:      opts.Name,
+			Src:       opts.Src,
+			YarnCache: opts.YarnCache,
+			Scripts:   splitList(scripts),
+			Shard:     opts.Shard,
+		},
+	})
+}
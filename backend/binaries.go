@@ -0,0 +1,103 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-build/versions"
+)
+
+// A Binary is a Go main package in the Grafana source that is compiled into an executable in the 'bin' folder.
+type Binary struct {
+	// Name is the name of the executable. '.exe' is appended to it for Windows distributions.
+	Name string `json:"name"`
+	// Package is the path of the main package in the source, like 'pkg/cmd/grafana'. If it is empty, 'pkg/cmd/{name}' is used.
+	Package string `json:"package,omitempty"`
+	// Tags are added to the build tags of the artifact when this binary is compiled.
+	Tags []string `json:"tags,omitempty"`
+	// LDFlags are added to the ldflags of the artifact when this binary is compiled, like `{"-X": ["main.foo=bar"]}`.
+	LDFlags map[string][]string `json:"ldflags,omitempty"`
+	// Optional binaries are skipped if their main package doesn't exist in the source.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// MainPackage returns the path of the main package of the binary.
+func (b Binary) MainPackage() string {
+	if b.Package != "" {
+		return b.Package
+	}
+
+	return path.Join("pkg", "cmd", b.Name)
+}
+
+// ShellCommand returns the shell command that runs the 'go build' command of the binary.
+// If the binary is optional, then the command is only run if its main package exists.
+func (b Binary) ShellCommand(cmd []string) string {
+	if !b.Optional {
+		return strings.Join(cmd, " ")
+	}
+
+	return fmt.Sprintf("if [ -d %s ]; then %s; fi", b.MainPackage(), strings.Join(cmd, " "))
+}
+
+// DefaultBinaries returns the binaries that are in the source of the given Grafana version.
+func DefaultBinaries(version string) []Binary {
+	opts := versions.OptionsFor(version)
+
+	binaries := []Binary{}
+	if opts.CombinedExecutable.Value {
+		binaries = append(binaries, Binary{Name: "grafana"})
+	}
+
+	// The versions that have 'grafana-example-apiserver' haven't been checked against the Grafana tags, so it is built for every version where its main package exists.
+	return append(binaries,
+		Binary{Name: "grafana-server"},
+		Binary{Name: "grafana-cli"},
+		Binary{Name: "grafana-example-apiserver", Optional: true},
+	)
+}
+
+// DefaultBinariesKey is the key in a BinariesManifest that is used for the package names that aren't in it.
+const DefaultBinariesKey = "default"
+
+// A BinariesManifest maps a package name, like 'grafana-enterprise', to the binaries that are built for it.
+type BinariesManifest map[string][]Binary
+
+// ReadBinariesManifest reads a BinariesManifest from the JSON file at path.
+func ReadBinariesManifest(path string) (BinariesManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := BinariesManifest{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("error parsing binaries manifest '%s': %w", path, err)
+	}
+
+	for name, binaries := range m {
+		for _, v := range binaries {
+			if v.Name == "" {
+				return nil, fmt.Errorf("binaries manifest '%s': a binary for '%s' has no name", path, name)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Binaries returns the binaries for the package name from the manifest.
+// If the package name isn't in the manifest, then the binaries under DefaultBinariesKey are returned, or the DefaultBinaries of the version if there are none.
+func (m BinariesManifest) Binaries(name string, version string) []Binary {
+	if b, ok := m[name]; ok {
+		return b
+	}
+	if b, ok := m[DefaultBinariesKey]; ok {
+		return b
+	}
+
+	return DefaultBinaries(version)
+}
@@ -0,0 +1,105 @@
+package backend_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+)
+
+func binaryNames(b []backend.Binary) []string {
+	names := make([]string, len(b))
+	for i, v := range b {
+		names[i] = v.Name
+	}
+	return names
+}
+
+func TestDefaultBinaries(t *testing.T) {
+	versions := map[string][]string{
+		"v9.2.0":  {"grafana-server", "grafana-cli", "grafana-example-apiserver"},
+		"v9.5.0":  {"grafana", "grafana-server", "grafana-cli", "grafana-example-apiserver"},
+		"v10.3.1": {"grafana", "grafana-server", "grafana-cli", "grafana-example-apiserver"},
+		"v11.0.0": {"grafana", "grafana-server", "grafana-cli", "grafana-example-apiserver"},
+	}
+
+	for version, expect := range versions {
+		if names := binaryNames(backend.DefaultBinaries(version)); !reflect.DeepEqual(names, expect) {
+			t.Errorf("%s: expected binaries %v but got %v", version, expect, names)
+		}
+	}
+}
+
+func TestBinariesManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "binaries.json")
+	manifest := `{
+		"grafana-enterprise": [
+			{"name": "grafana", "ldflags": {"-X": ["main.edition=enterprise"]}},
+			{"name": "grafana-tool", "package": "pkg/extensions/cmd/tool", "tags": ["enterprise"]}
+		],
+		"default": [{"name": "grafana"}]
+	}`
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := backend.ReadBinariesManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enterprise := m.Binaries("grafana-enterprise", "v10.2.0")
+	if names := binaryNames(enterprise); !reflect.DeepEqual(names, []string{"grafana", "grafana-tool"}) {
+		t.Errorf("unexpected enterprise binaries %v", names)
+	}
+	if p := enterprise[0].MainPackage(); p != "pkg/cmd/grafana" {
+		t.Errorf("expected the main package of 'grafana' to be 'pkg/cmd/grafana' but got '%s'", p)
+	}
+	if p := enterprise[1].MainPackage(); p != "pkg/extensions/cmd/tool" {
+		t.Errorf("expected the main package of 'grafana-tool' to be 'pkg/extensions/cmd/tool' but got '%s'", p)
+	}
+	if v := enterprise[0].LDFlags["-X"]; !reflect.DeepEqual(v, []string{"main.edition=enterprise"}) {
+		t.Errorf("unexpected ldflags %v", v)
+	}
+
+	if names := binaryNames(m.Binaries("grafana", "v10.2.0")); !reflect.DeepEqual(names, []string{"grafana"}) {
+		t.Errorf("expected the default binaries to be used for 'grafana' but got %v", names)
+	}
+
+	if names := binaryNames(backend.BinariesManifest{}.Binaries("grafana", "v9.2.0")); !reflect.DeepEqual(names, []string{"grafana-server", "grafana-cli", "grafana-example-apiserver"}) {
+		t.Errorf("expected the binaries of the version to be used without a manifest but got %v", names)
+	}
+}
+
+func TestBinariesManifestWithoutName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "binaries.json")
+	if err := os.WriteFile(path, []byte(`{"grafana": [{"package": "pkg/cmd/grafana"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backend.ReadBinariesManifest(path); err == nil {
+		t.Error("expected an error for a binary without a name")
+	}
+}
+
+func TestBinaryShellCommand(t *testing.T) {
+	cmd := []string{"go", "build", "-o", "bin/grafana-example-apiserver", "./pkg/cmd/grafana-example-apiserver"}
+
+	required := backend.Binary{Name: "grafana-example-apiserver"}
+	if c := required.ShellCommand(cmd); c != "go build -o bin/grafana-example-apiserver ./pkg/cmd/grafana-example-apiserver" {
+		t.Errorf("expected a required binary to always be built but got '%s'", c)
+	}
+
+	optional := backend.Binary{Name: "grafana-example-apiserver", Optional: true}
+	if c := optional.ShellCommand(cmd); c != "if [ -d pkg/cmd/grafana-example-apiserver ]; then go build -o bin/grafana-example-apiserver ./pkg/cmd/grafana-example-apiserver; fi" {
+		t.Errorf("expected an optional binary to only be built if its package exists but got '%s'", c)
+	}
+
+	for _, v := range backend.DefaultBinaries("v10.3.1") {
+		if v.Optional != (v.Name == "grafana-example-apiserver") {
+			t.Errorf("expected only grafana-example-apiserver to be optional but '%s' has optional=%t", v.Name, v.Optional)
+		}
+	}
+}
@@ -26,18 +26,33 @@ func GoLDFlags(flags map[string][]string) string {
 }
 
 // GoBuildCommand returns the arguments for go build to be used in 'WithExec'.
-func GoBuildCommand(output string, ldflags map[string][]string, tags []string, main string) []string {
+// The extra arguments, like '-pgo=...' or '-race', are added before the main package.
+func GoBuildCommand(output string, ldflags map[string][]string, tags []string, main string, extra ...string) []string {
 	args := []string{"go", "build",
 		fmt.Sprintf("-ldflags=\"%s\"", GoLDFlags(ldflags)),
 		fmt.Sprintf("-o=%s", output),
 		"-trimpath",
 		fmt.Sprintf("-tags=%s", strings.Join(tags, ",")),
-		// Go is weird and paths referring to packages within a module to be prefixed with "./".
-		// Otherwise, the path is assumed to be relative to $GOROOT
-		"./" + main,
 	}
 
-	return args
+	args = append(args, extra...)
+
+	// Go is weird and paths referring to packages within a module to be prefixed with "./".
+	// Otherwise, the path is assumed to be relative to $GOROOT
+	return append(args, "./"+main)
+}
+
+// withLDFlags returns the ldflags of the build with the ldflags of a binary added to them.
+func withLDFlags(ldflags map[string][]string, extra map[string][]string) map[string][]string {
+	flags := make(map[string][]string, len(ldflags)+len(extra))
+	for k, v := range ldflags {
+		flags[k] = v
+	}
+	for k, v := range extra {
+		flags[k] = append(append([]string{}, flags[k]...), v...)
+	}
+
+	return flags
 }
 
 func Build(
@@ -54,27 +69,36 @@ func Build(
 		ldflags = LDFlagsStatic(vcsinfo)
 	}
 
-	cmd := []string{
-		"grafana",
-		"grafana-server",
-		"grafana-cli",
-		"grafana-example-apiserver",
+	binaries := opts.Binaries
+	if len(binaries) == 0 {
+		binaries = DefaultBinaries(opts.Version)
 	}
 
 	os, _ := OSAndArch(distro)
 
-	for _, v := range cmd {
-		// Some CLI packages such as grafana-example-apiserver don't exist in earlier Grafana Versions <10.3
-		// Below check skips building them as needed
-		pkgPath := path.Join("pkg", "cmd", v)
-		out := path.Join(out, v)
+	args := opts.Instrumentation.Args()
+	if opts.PGOProfile != nil {
+		builder = builder.WithMountedFile(PGOProfilePath, opts.PGOProfile.File)
+		args = append(args, fmt.Sprintf("-pgo=%s", PGOProfilePath))
+	}
+	if opts.Instrumentation.Race {
+		builder = builder.WithEnvVariable("CGO_ENABLED", "1")
+	}
+
+	for _, v := range binaries {
+		out := path.Join(out, v.Name)
 		if os == "windows" {
 			out += ".exe"
 		}
 
-		cmd := GoBuildCommand(out, ldflags, opts.Tags, pkgPath)
-		builder = builder.
-			WithExec([]string{"/bin/sh", "-c", fmt.Sprintf(`if [ -d %s ]; then %s; fi`, pkgPath, strings.Join(cmd, " "))})
+		var (
+			tags    = append(append([]string{}, opts.Tags...), v.Tags...)
+			ldflags = withLDFlags(ldflags, v.LDFlags)
+		)
+
+		// The ldflags are quoted for the shell.
+		cmd := GoBuildCommand(out, ldflags, tags, v.MainPackage(), args...)
+		builder = builder.WithExec([]string{"/bin/sh", "-c", v.ShellCommand(cmd)})
 	}
 
 	return builder.Directory(out)
@@ -0,0 +1,28 @@
+package backend_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+)
+
+func TestGoBuildCommandExtraArgs(t *testing.T) {
+	cmd := backend.GoBuildCommand("bin/grafana", nil, nil, "pkg/cmd/grafana", "-pgo=/tmp/grafana.pgo")
+	if n := len(cmd); cmd[n-2] != "-pgo=/tmp/grafana.pgo" || cmd[n-1] != "./pkg/cmd/grafana" {
+		t.Errorf("expected '-pgo' before the main package but got '%v'", cmd)
+	}
+
+	for _, v := range backend.GoBuildCommand("bin/grafana", nil, nil, "pkg/cmd/grafana") {
+		if strings.HasPrefix(v, "-pgo") {
+			t.Errorf("expected no '-pgo' argument without a profile but got '%s'", v)
+		}
+	}
+}
+
+func TestPGOProfileSuffix(t *testing.T) {
+	p := &backend.PGOProfile{Sha256: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+	if s := p.Suffix(); s != "pgo-0123456789ab" {
+		t.Errorf("expected 'pgo-0123456789ab' but got '%s'", s)
+	}
+}
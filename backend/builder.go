@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
@@ -18,13 +19,48 @@ type BuildOpts struct {
 	WireTag           string
 	Static            bool
 	Enterprise        bool
+
+	// Binaries are the Go main packages that are compiled. If it is empty, the DefaultBinaries of the Version are compiled.
+	Binaries []Binary
+
+	// GoModCache is the name of the cache volume of the Go module cache, which is usually the golang.ModuleCacheKey of the go.sum of the source.
+	// It is computed once by the caller, because reading go.sum runs the dag that creates the source.
+	// GoBuildCache overrides the name of the GOCACHE volume. If it is empty, the name from BuildCacheKey is used.
+	GoModCache   string
+	GoBuildCache string
+
+	// PGOProfile is the profile that the binaries are optimized with. If it is nil, Go's default ('default.pgo' in the main package) is used.
+	PGOProfile *PGOProfile
+
+	// Instrumentation builds the binaries with the race detector or coverage counters.
+	Instrumentation Instrumentation
+}
+
+// BuildCacheKey returns the name of the GOCACHE volume for the Go version, distribution, and C toolchain.
+// Go keys the entries of its build cache by target, so a volume could be shared; one per distribution keeps parallel builds from competing for it.
+func BuildCacheKey(goVersion string, distro Distribution) string {
+	c, _ := DistroCapabilities(distro)
+	return fmt.Sprintf("go-build-%s-%s-%s", goVersion, strings.ReplaceAll(string(distro), "/", "-"), c.Toolchain)
+}
+
+func withBuildCache(d *dagger.Client, container *dagger.Container, distro Distribution, goVersion string, opts *BuildOpts) *dagger.Container {
+	key := opts.GoBuildCache
+	if key == "" {
+		key = BuildCacheKey(goVersion, distro)
+	}
+
+	return golang.WithBuildCache(container, d.CacheVolume(key))
 }
 
 func distroOptsFunc(log *slog.Logger, distro Distribution) (DistroBuildOptsFunc, error) {
-	if val, ok := DistributionGoOpts[distro]; ok {
+	c, err := DistroCapabilities(distro)
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := ToolchainGoOpts[c.Toolchain]; ok {
 		return DistroOptsLogger(log, val), nil
 	}
-	return nil, errors.New("unrecognized distirbution")
+	return nil, fmt.Errorf("%s: unrecognized toolchain '%s'", distro, c.Toolchain)
 }
 
 func WithGoEnv(log *slog.Logger, container *dagger.Container, distro Distribution, opts *BuildOpts) (*dagger.Container, error) {
@@ -32,7 +68,10 @@ func WithGoEnv(log *slog.Logger, container *dagger.Container, distro Distributio
 	if err != nil {
 		return nil, err
 	}
-	bopts := fn(distro, opts.ExperimentalFlags, opts.Tags)
+	bopts, err := fn(distro, opts.ExperimentalFlags, opts.Tags)
+	if err != nil {
+		return nil, err
+	}
 
 	return containers.WithEnv(container, GoBuildEnv(bopts)), nil
 }
@@ -42,7 +81,10 @@ func WithViceroyEnv(log *slog.Logger, container *dagger.Container, distro Distri
 	if err != nil {
 		return nil, err
 	}
-	bopts := fn(distro, opts.ExperimentalFlags, opts.Tags)
+	bopts, err := fn(distro, opts.ExperimentalFlags, opts.Tags)
+	if err != nil {
+		return nil, err
+	}
 
 	return containers.WithEnv(container, ViceroyEnv(bopts)), nil
 }
@@ -73,9 +115,17 @@ func ViceroyContainer(
 		WithExec([]string{"/bin/sh", "-c", fmt.Sprintf("curl -L %s | tar -C /usr/local -xzf -", goURL)}).
 		WithEnvVariable("PATH", "/bin:/usr/bin:/usr/local/bin:/usr/local/go/bin:/usr/osxcross/bin")
 
+	container = withBuildCache(d, container, distro, goVersion, opts)
+
 	return WithViceroyEnv(log, container, distro, opts)
 }
 
+// UsesViceroy returns true if the distro is cross-compiled in a viceroy container, which is the case for all darwin distros and only windows/amd64.
+func UsesViceroy(distro Distribution) bool {
+	c, _ := DistroCapabilities(distro)
+	return c.Toolchain == ToolchainViceroy
+}
+
 func GolangContainer(
 	d *dagger.Client,
 	log *slog.Logger,
@@ -85,9 +135,7 @@ func GolangContainer(
 	distro Distribution,
 	opts *BuildOpts,
 ) (*dagger.Container, error) {
-	os, _ := OSAndArch(distro)
-	// Only use viceroy for all darwin and only windows/amd64
-	if os == "darwin" || distro == DistWindowsAMD64 {
+	if UsesViceroy(distro) {
 		return ViceroyContainer(d, log, distro, goVersion, viceroyVersion, opts)
 	}
 
@@ -99,6 +147,8 @@ func GolangContainer(
 		WithExec([]string{"wget", "http://musl.cc/arm-linux-musleabihf-cross.tgz", "-P", "/toolchain"}).
 		WithExec([]string{"tar", "-xvf", "/toolchain/arm-linux-musleabihf-cross.tgz", "-C", "/toolchain"})
 
+	container = withBuildCache(d, container, distro, goVersion, opts)
+
 	return WithGoEnv(log, container, distro, opts)
 }
 
@@ -106,7 +156,8 @@ func GolangContainer(
 // The build container:
 // * Will be based on rfratto/viceroy for Darwin or Windows
 // * Will be based on golang:x.y.z-alpine for all other ditsros
-// * Will download & cache the downloaded Go modules
+// * Will download & cache the downloaded Go modules in a volume keyed by the hash of go.sum
+// * Will cache the compiled packages in a GOCACHE volume keyed by the Go version, distro, and C toolchain
 // * Will run `make gen-go` on the provided Grafana source
 //   - With the linux/amd64 arch/os combination, regardless of what the requested distro is.
 //
@@ -121,11 +172,13 @@ func Builder(
 	goVersion string,
 	viceroyVersion string,
 ) (*dagger.Container, error) {
+	if opts.GoModCache == "" {
+		return nil, errors.New("no name for the go module cache volume")
+	}
+
 	var (
-		version  = opts.Version
-		cacheKey = "go-mod-" + version
 		cacheDir = golang.ModuleDir(d, platform, src, goVersion)
-		cache    = d.CacheVolume(cacheKey)
+		cache    = d.CacheVolume(opts.GoModCache)
 	)
 
 	// make gen-go creates a file at "pkg/server/wire_gen.go".
@@ -0,0 +1,22 @@
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+)
+
+func TestBuildCacheKey(t *testing.T) {
+	keys := map[backend.Distribution]string{
+		backend.DistLinuxAMD64:            "go-build-1.21.3-linux-amd64-zig",
+		backend.DistLinuxARMv7:            "go-build-1.21.3-linux-arm-v7-musl-cross",
+		backend.DistDarwinARM64:           "go-build-1.21.3-darwin-arm64-viceroy",
+		backend.DistLinuxAMD64DynamicMusl: "go-build-1.21.3-linux-amd64-dynamic-musl-native",
+	}
+
+	for d, v := range keys {
+		if k := backend.BuildCacheKey("1.21.3", d); k != v {
+			t.Errorf("%s: expected key '%s' but got '%s'", d, v, k)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"dagger.io/dagger"
+)
+
+// Toolchain is the C toolchain that is used to compile the backend with CGO enabled.
+type Toolchain string
+
+const (
+	// ToolchainZig cross-compiles with 'zig cc' using the ZigTarget of the distribution.
+	ToolchainZig Toolchain = "zig"
+	// ToolchainViceroy cross-compiles in a rfratto/viceroy container.
+	ToolchainViceroy Toolchain = "viceroy"
+	// ToolchainMuslCross uses the musl.cc cross compiler that is installed in /toolchain.
+	ToolchainMuslCross Toolchain = "musl-cross"
+	// ToolchainNative uses the C compiler of the build container.
+	ToolchainNative Toolchain = "native"
+)
+
+// Capabilities describes how a distribution is built and what can be produced for it.
+type Capabilities struct {
+	Toolchain Toolchain
+	// ZigTarget is the value of the '-target' argument of 'zig cc' when the Toolchain is ToolchainZig.
+	ZigTarget string
+
+	// Static is true if the binaries are linked statically.
+	Static bool
+
+	// DockerPlatform is the platform of the images and of the containers that verify the packages.
+	// If it is empty, docker images can't be built for the distribution.
+	DockerPlatform dagger.Platform
+
+	// DebArch and RPMArch are the architecture names in the deb and rpm packages.
+	// If they are empty, the package type can't be built for the distribution.
+	DebArch string
+	RPMArch string
+
+	// Verify is true if the packages can be verified by running Grafana in a container.
+	Verify bool
+
+	// Race is true if the binaries can be built with the race detector, which needs a libc that its runtime supports.
+	Race bool
+}
+
+var ErrorUnsupportedDistribution = errors.New("unsupported distribution")
+
+// DistributionCapabilities lists every distribution that can be built. Distributions that are not in this table are rejected when the artifact string is parsed.
+var DistributionCapabilities = map[Distribution]Capabilities{
+	DistLinuxAMD64: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "x86_64-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/amd64",
+		DebArch:        "amd64",
+		RPMArch:        "x86_64",
+		Verify:         true,
+	},
+	// The race detector is only supported for the glibc builds on Linux; its runtime doesn't work with musl or static linking.
+	DistLinuxAMD64Dynamic: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "x86_64-linux-gnu",
+		DockerPlatform: "linux/amd64",
+		DebArch:        "amd64",
+		RPMArch:        "x86_64",
+		Verify:         true,
+		Race:           true,
+	},
+	DistLinuxAMD64DynamicMusl: {
+		Toolchain:      ToolchainNative,
+		DockerPlatform: "linux/amd64",
+		DebArch:        "amd64",
+		RPMArch:        "x86_64",
+		Verify:         true,
+	},
+	DistLinuxARM64: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "aarch64-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/arm64",
+		DebArch:        "arm64",
+		RPMArch:        "aarch64",
+		Verify:         true,
+	},
+	DistLinuxARM64Dynamic: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "aarch64-linux-musl",
+		DockerPlatform: "linux/arm64",
+		DebArch:        "arm64",
+		RPMArch:        "aarch64",
+		Verify:         true,
+	},
+	// armv6 binaries run in armv7 containers because there are no armv6 images for most of the base images.
+	// The rpm architecture stays 'armhf' for compatibility with the packages that were already published.
+	DistLinuxARMv6: {
+		Toolchain:      ToolchainMuslCross,
+		Static:         true,
+		DockerPlatform: "linux/arm/v7",
+		DebArch:        "armhf",
+		RPMArch:        "armhf",
+		Verify:         true,
+	},
+	DistLinuxARMv7: {
+		Toolchain:      ToolchainMuslCross,
+		Static:         true,
+		DockerPlatform: "linux/arm/v7",
+		DebArch:        "armhf",
+		RPMArch:        "armhf",
+		Verify:         true,
+	},
+	// Verifying riscv64 is unsupported because alpine and ubuntu don't have riscv64 images yet.
+	DistLinuxRISCV64: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "riscv64-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/riscv64",
+		DebArch:        "riscv64",
+		RPMArch:        "riscv64",
+	},
+	// s390x and ppc64le are verified under emulation in the ubuntu, debian, and ubi images that exist for them.
+	DistLinuxS390X: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "s390x-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/s390x",
+		DebArch:        "s390x",
+		RPMArch:        "s390x",
+		Verify:         true,
+	},
+	DistLinuxPPC64le: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "powerpc64le-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/ppc64le",
+		DebArch:        "ppc64el",
+		RPMArch:        "ppc64le",
+		Verify:         true,
+	},
+	// Verifying loong64 is unsupported because ubuntu and ubi don't have loong64 images.
+	DistLinuxLoong64: {
+		Toolchain:      ToolchainZig,
+		ZigTarget:      "loongarch64-linux-musl",
+		Static:         true,
+		DockerPlatform: "linux/loong64",
+		DebArch:        "loong64",
+		RPMArch:        "loongarch64",
+	},
+	// FreeBSD binaries are linked dynamically against the FreeBSD libc that zig provides for its freebsd targets.
+	DistFreeBSDAMD64: {
+		Toolchain: ToolchainZig,
+		ZigTarget: "x86_64-freebsd",
+	},
+	DistFreeBSDARM64: {
+		Toolchain: ToolchainZig,
+		ZigTarget: "aarch64-freebsd",
+	},
+	DistDarwinAMD64: {
+		Toolchain: ToolchainViceroy,
+		Race:      true,
+	},
+	DistDarwinARM64: {
+		Toolchain: ToolchainViceroy,
+		Race:      true,
+	},
+	DistWindowsAMD64: {
+		Toolchain: ToolchainViceroy,
+		Race:      true,
+	},
+	DistWindowsARM64: {
+		Toolchain: ToolchainZig,
+		ZigTarget: "aarch64-windows-gnu",
+	},
+}
+
+// DistroCapabilities returns the capabilities of the distribution, or ErrorUnsupportedDistribution if it can't be built.
+func DistroCapabilities(d Distribution) (Capabilities, error) {
+	c, ok := DistributionCapabilities[d]
+	if !ok {
+		return Capabilities{}, fmt.Errorf("%s: %w", d, ErrorUnsupportedDistribution)
+	}
+
+	return c, nil
+}
+
+// SupportedDistributions returns the distributions in the DistributionCapabilities table, sorted by name.
+func SupportedDistributions() []Distribution {
+	d := make([]Distribution, 0, len(DistributionCapabilities))
+	for k := range DistributionCapabilities {
+		d = append(d, k)
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+
+	return d
+}
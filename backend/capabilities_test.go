@@ -0,0 +1,80 @@
+package backend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+)
+
+func TestDistributionCapabilities(t *testing.T) {
+	for d, c := range backend.DistributionCapabilities {
+		if _, ok := backend.ToolchainGoOpts[c.Toolchain]; !ok {
+			t.Errorf("%s: toolchain '%s' has no build options", d, c.Toolchain)
+		}
+		if c.Toolchain == backend.ToolchainZig && c.ZigTarget == "" {
+			t.Errorf("%s: uses zig but has no zig target", d)
+		}
+		if c.Verify && c.DockerPlatform == "" {
+			t.Errorf("%s: can be verified but has no docker platform to run in", d)
+		}
+		if c.Race && c.Static {
+			t.Errorf("%s: the race detector doesn't support static binaries", d)
+		}
+	}
+}
+
+func TestZigCC(t *testing.T) {
+	cc, err := backend.ZigCC(backend.DistLinuxARM64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "zig cc -target aarch64-linux-musl"; cc != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, cc)
+	}
+
+	unsupported := []backend.Distribution{
+		backend.DistPlan9AMD64,
+		backend.DistDarwinARM64,
+	}
+	for _, d := range unsupported {
+		if _, err := backend.ZigCC(d); !errors.Is(err, backend.ErrorUnsupportedDistribution) {
+			t.Errorf("%s: expected ErrorUnsupportedDistribution but got '%v'", d, err)
+		}
+	}
+}
+
+func TestPackageArch(t *testing.T) {
+	archs := map[backend.Distribution][2]string{
+		backend.DistLinuxAMD64:   {"amd64", "x86_64"},
+		backend.DistLinuxARM64:   {"arm64", "aarch64"},
+		backend.DistLinuxARMv6:   {"armhf", "armhf"},
+		backend.DistLinuxPPC64le: {"ppc64el", "ppc64le"},
+		backend.DistLinuxLoong64: {"loong64", "loongarch64"},
+		backend.DistWindowsAMD64: {"", ""},
+		backend.DistSolarisAMD64: {"", ""},
+	}
+
+	for d, v := range archs {
+		if a := backend.PackageArch(d, "deb"); a != v[0] {
+			t.Errorf("%s: expected deb arch '%s' but got '%s'", d, v[0], a)
+		}
+		if a := backend.PackageArch(d, "rpm"); a != v[1] {
+			t.Errorf("%s: expected rpm arch '%s' but got '%s'", d, v[1], a)
+		}
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	platforms := map[backend.Distribution]string{
+		backend.DistLinuxARMv6:            "linux/arm/v7",
+		backend.DistLinuxAMD64DynamicMusl: "linux/amd64",
+		backend.DistDarwinAMD64:           "",
+	}
+
+	for d, v := range platforms {
+		if p := backend.Platform(d); string(p) != v {
+			t.Errorf("%s: expected platform '%s' but got '%s'", d, v, p)
+		}
+	}
+}
@@ -171,26 +171,29 @@ func ArchVersion(d Distribution) string {
 	return p[2]
 }
 
-func PackageArch(d Distribution) string {
-	_, arch := OSAndArch(d)
+// PackageArch returns the architecture name of the distribution in a package of the given type ("deb" or "rpm").
+// It returns an empty string if the package type can't be built for the distribution.
+func PackageArch(d Distribution, packageType string) string {
+	c, err := DistroCapabilities(d)
+	if err != nil {
+		return ""
+	}
 
-	if arch == "arm" {
-		return "armhf"
+	if packageType == "rpm" {
+		return c.RPMArch
 	}
 
-	return arch
+	return c.DebArch
 }
 
-// From the distribution, try to assume the docker platform (used in Docker's --platform argument or the (dagger.ContainerOpts).Platform field
+// Platform returns the docker platform of the distribution (used in Docker's --platform argument or the (dagger.ContainerOpts).Platform field).
+// It returns an empty platform, which is the platform of the dagger engine, if docker images can't be built for the distribution.
 func Platform(d Distribution) dagger.Platform {
-	p := strings.ReplaceAll(string(d), "/dynamic-musl", "")
-	p = strings.ReplaceAll(p, "/dynamic", "")
-	p = strings.ReplaceAll(p, "arm/v6", "arm/v7")
-	// for now let's just try to use the distro name as the platform and see if that works...
-	return dagger.Platform(p)
+	c, _ := DistroCapabilities(d)
+	return c.DockerPlatform
 }
 
-type DistroBuildOptsFunc func(distro Distribution, experiments []string, tags []string) *GoBuildOpts
+type DistroBuildOptsFunc func(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error)
 
 func LDFlagsStatic(info *VCSInfo) map[string][]string {
 	return map[string][]string{
@@ -208,39 +211,38 @@ func LDFlagsDynamic(info *VCSInfo) map[string][]string {
 	}
 }
 
-func ZigCC(distro Distribution) string {
-	target, ok := ZigTargets[distro]
-	if !ok {
-		target = "x86_64-linux-musl" // best guess? should probably retun an error but i don't want to
+func zigTarget(distro Distribution) (string, error) {
+	c, err := DistroCapabilities(distro)
+	if err != nil {
+		return "", err
+	}
+	if c.ZigTarget == "" {
+		return "", fmt.Errorf("%s: no zig target: %w", distro, ErrorUnsupportedDistribution)
 	}
 
-	return fmt.Sprintf("zig cc -target %s", target)
+	return c.ZigTarget, nil
 }
 
-func ZigCXX(distro Distribution) string {
-	target, ok := ZigTargets[distro]
-	if !ok {
-		target = "x86_64-linux-musl" // best guess? should probably retun an error but i don't want to
+func ZigCC(distro Distribution) (string, error) {
+	target, err := zigTarget(distro)
+	if err != nil {
+		return "", err
 	}
 
-	return fmt.Sprintf("zig c++ -target %s", target)
+	return fmt.Sprintf("zig cc -target %s", target), nil
 }
 
-var DefaultBuildOpts = func(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
-	os, arch := OSAndArch(distro)
-
-	return &GoBuildOpts{
-		CC:                ZigCC(distro),
-		CXX:               ZigCXX(distro),
-		ExperimentalFlags: experiments,
-		OS:                os,
-		Arch:              arch,
-		CGOEnabled:        true,
+func ZigCXX(distro Distribution) (string, error) {
+	target, err := zigTarget(distro)
+	if err != nil {
+		return "", err
 	}
+
+	return fmt.Sprintf("zig c++ -target %s", target), nil
 }
 
 // BuildOptsDynamicARM builds Grafana statically for the armv6/v7 architectures (not aarch64/arm64)
-func BuildOptsDynamicARM(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
+func BuildOptsDynamicARM(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
 	var (
 		os, _ = OSAndArch(distro)
 		arm   = ArchVersion(distro)
@@ -254,11 +256,11 @@ func BuildOptsDynamicARM(distro Distribution, experiments []string, tags []strin
 		Arch:              "arm",
 		GoARM:             GoARM(arm),
 		CGOEnabled:        true,
-	}
+	}, nil
 }
 
 // BuildOptsStaticARM builds Grafana statically for the armv6/v7 architectures (not aarch64/arm64)
-func BuildOptsStaticARM(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
+func BuildOptsStaticARM(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
 	var (
 		os, _ = OSAndArch(distro)
 		arm   = ArchVersion(distro)
@@ -272,25 +274,34 @@ func BuildOptsStaticARM(distro Distribution, experiments []string, tags []string
 		Arch:              "arm",
 		GoARM:             GoARM(arm),
 		CGOEnabled:        true,
-	}
+	}, nil
 }
 
-func StdZigBuildOpts(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
+func StdZigBuildOpts(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
 	var (
 		os, arch = OSAndArch(distro)
 	)
 
+	cc, err := ZigCC(distro)
+	if err != nil {
+		return nil, err
+	}
+	cxx, err := ZigCXX(distro)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GoBuildOpts{
-		CC:                ZigCC(distro),
-		CXX:               ZigCXX(distro),
+		CC:                cc,
+		CXX:               cxx,
 		ExperimentalFlags: experiments,
 		OS:                os,
 		Arch:              arch,
 		CGOEnabled:        true,
-	}
+	}, nil
 }
 
-func BuildOptsWithoutZig(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
+func BuildOptsWithoutZig(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
 	var (
 		os, arch = OSAndArch(distro)
 	)
@@ -300,10 +311,10 @@ func BuildOptsWithoutZig(distro Distribution, experiments []string, tags []strin
 		OS:                os,
 		Arch:              arch,
 		CGOEnabled:        true,
-	}
+	}, nil
 }
 
-func ViceroyBuildOpts(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
+func ViceroyBuildOpts(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
 	var (
 		os, arch = OSAndArch(distro)
 	)
@@ -314,49 +325,26 @@ func ViceroyBuildOpts(distro Distribution, experiments []string, tags []string)
 		OS:                os,
 		Arch:              arch,
 		CGOEnabled:        true,
-	}
-}
-
-var ZigTargets = map[Distribution]string{
-	DistLinuxAMD64:            "x86_64-linux-musl",
-	DistLinuxAMD64Dynamic:     "x86_64-linux-gnu",
-	DistLinuxAMD64DynamicMusl: "x86_64-linux-musl",
-	DistLinuxARM64:            "aarch64-linux-musl",
-	DistLinuxARM64Dynamic:     "aarch64-linux-musl",
-	DistLinuxARM:              "arm-linux-musleabihf",
-	DistLinuxARMv6:            "arm-linux-musleabihf",
-	DistLinuxARMv7:            "arm-linux-musleabihf",
-	DistLinuxRISCV64:          "riscv64-linux-musl",
-	DistWindowsAMD64:          "x86_64-windows-gnu",
-	DistWindowsARM64:          "aarch64-windows-gnu",
+	}, nil
 }
 
-var DistributionGoOpts = map[Distribution]DistroBuildOptsFunc{
-	// The Linux distros should all have an equivalent zig target in the ZigTargets map
-	DistLinuxARM:          BuildOptsStaticARM,
-	DistLinuxARMv6:        BuildOptsStaticARM,
-	DistLinuxARMv7:        BuildOptsStaticARM,
-	DistLinuxARM64:        StdZigBuildOpts,
-	DistLinuxARM64Dynamic: StdZigBuildOpts,
-	DistLinuxAMD64:        StdZigBuildOpts,
-	DistLinuxAMD64Dynamic: StdZigBuildOpts,
-	DistPlan9AMD64:        StdZigBuildOpts,
-	DistLinuxRISCV64:      StdZigBuildOpts,
-
-	// Non-Linux distros can have whatever they want in CC and CXX; it'll get overridden
-	// but it's probably not best to rely on that.
-	DistWindowsAMD64: ViceroyBuildOpts,
-	DistWindowsARM64: StdZigBuildOpts,
-	DistDarwinAMD64:  ViceroyBuildOpts,
-	DistDarwinARM64:  ViceroyBuildOpts,
-
-	DistLinuxAMD64DynamicMusl: BuildOptsWithoutZig,
+// ToolchainGoOpts are the functions that set the go build options for the toolchain of a distribution.
+// Non-Linux distros can have whatever they want in CC and CXX; it'll get overridden
+// but it's probably not best to rely on that.
+var ToolchainGoOpts = map[Toolchain]DistroBuildOptsFunc{
+	ToolchainZig:       StdZigBuildOpts,
+	ToolchainViceroy:   ViceroyBuildOpts,
+	ToolchainMuslCross: BuildOptsStaticARM,
+	ToolchainNative:    BuildOptsWithoutZig,
 }
 
-func DistroOptsLogger(log *slog.Logger, fn DistroBuildOptsFunc) func(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
-	return func(distro Distribution, experiments []string, tags []string) *GoBuildOpts {
-		opts := fn(distro, experiments, tags)
+func DistroOptsLogger(log *slog.Logger, fn DistroBuildOptsFunc) DistroBuildOptsFunc {
+	return func(distro Distribution, experiments []string, tags []string) (*GoBuildOpts, error) {
+		opts, err := fn(distro, experiments, tags)
+		if err != nil {
+			return nil, err
+		}
 		log.Debug("Building with options", "distribution", distro, "experiments", experiments, "tags", tags, "os", opts.OS, "arch", opts.Arch, "arm", opts.GoARM, "CGO", opts.CGOEnabled, "386", opts.Go386, "CC", opts.CC, "CXX", opts.CXX)
-		return opts
+		return opts, nil
 	}
 }
@@ -0,0 +1,43 @@
+package backend
+
+import "strings"
+
+// Instrumentation adds the race detector or coverage counters to the binaries, for testing environments.
+type Instrumentation struct {
+	// Race builds with '-race'. It needs CGO and a distribution whose capabilities support it.
+	Race bool
+	// Cover builds with '-cover -covermode=atomic'. The binaries write coverage data to $GOCOVERDIR when it's set.
+	Cover bool
+}
+
+// Enabled returns true if the binaries are instrumented.
+func (i Instrumentation) Enabled() bool {
+	return i.Race || i.Cover
+}
+
+// Args returns the arguments that are added to 'go build'.
+func (i Instrumentation) Args() []string {
+	args := []string{}
+	if i.Race {
+		args = append(args, "-race")
+	}
+	if i.Cover {
+		// The race detector requires the atomic mode, and it's the only safe one for a concurrent server.
+		args = append(args, "-cover", "-covermode=atomic")
+	}
+
+	return args
+}
+
+// Suffix returns the name that distinguishes instrumented artifacts, like 'race', 'cover', or 'race-cover'. It's empty if nothing is instrumented.
+func (i Instrumentation) Suffix() string {
+	s := []string{}
+	if i.Race {
+		s = append(s, "race")
+	}
+	if i.Cover {
+		s = append(s, "cover")
+	}
+
+	return strings.Join(s, "-")
+}
@@ -0,0 +1,35 @@
+package backend_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+)
+
+func TestInstrumentation(t *testing.T) {
+	cases := map[string]struct {
+		Instrumentation backend.Instrumentation
+		Args            string
+		Suffix          string
+	}{
+		"none":       {backend.Instrumentation{}, "", ""},
+		"race":       {backend.Instrumentation{Race: true}, "-race", "race"},
+		"cover":      {backend.Instrumentation{Cover: true}, "-cover -covermode=atomic", "cover"},
+		"race-cover": {backend.Instrumentation{Race: true, Cover: true}, "-race -cover -covermode=atomic", "race-cover"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if args := strings.Join(c.Instrumentation.Args(), " "); args != c.Args {
+				t.Errorf("expected args '%s' but got '%s'", c.Args, args)
+			}
+			if s := c.Instrumentation.Suffix(); s != c.Suffix {
+				t.Errorf("expected suffix '%s' but got '%s'", c.Suffix, s)
+			}
+			if e := c.Instrumentation.Enabled(); e != (c.Suffix != "") {
+				t.Errorf("expected enabled to be %t but got %t", c.Suffix != "", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package backend
+
+import (
+	"fmt"
+
+	"dagger.io/dagger"
+)
+
+// PGOProfilePath is where the PGOProfile is mounted in the build container.
+const PGOProfilePath = "/tmp/grafana.pgo"
+
+// A PGOProfile is a CPU profile that the binaries are compiled with for profile-guided optimization.
+// If there is none, Go uses the 'default.pgo' file in the main package when it is present.
+type PGOProfile struct {
+	File *dagger.File
+	// Sha256 is the checksum of the profile. Builds with different profiles are different artifacts.
+	Sha256 string
+}
+
+// Suffix returns the name that distinguishes the backends that are built with this profile, like 'pgo-0123456789ab'.
+func (p *PGOProfile) Suffix() string {
+	sum := p.Sha256
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+
+	return fmt.Sprintf("pgo-%s", sum)
+}
@@ -0,0 +1,68 @@
+package backend
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/shards"
+)
+
+const (
+	// GotestsumVersion is the version of gotestsum that writes the JUnit report of the tests.
+	GotestsumVersion = "v1.11.0"
+
+	// TestResultsDir is the directory in the build container where the results of the tests are written.
+	TestResultsDir = "/tmp/backend-test"
+
+	// TestExitCodeFile is the name of the file in the results that has the exit code of 'go test'.
+	TestExitCodeFile = "exit-code"
+)
+
+type TestOpts struct {
+	// Packages are the package patterns to test, like './pkg/...'.
+	Packages []string
+	Tags     []string
+	Shard    shards.Shard
+}
+
+// GoTestCommand returns the shell command that lists the packages that match the patterns, tests the packages of the shard, and writes
+// 'packages.txt', 'junit.xml', 'coverage.out', 'test.log', and the exit code to the results directory.
+// Listing the packages fails the command, but failing tests don't, so that the results can be exported when tests fail.
+// The packages are dealt to the shards in turn, in the order that 'go list' returns them. A shard without packages passes.
+func GoTestCommand(results string, tags []string, patterns []string, shard shards.Shard) string {
+	var (
+		tagsArg  = fmt.Sprintf("-tags=%s", strings.Join(tags, ","))
+		packages = path.Join(results, "packages.txt")
+		exitCode = path.Join(results, TestExitCodeFile)
+	)
+
+	args := []string{"gotestsum",
+		fmt.Sprintf("--junitfile=%s", path.Join(results, "junit.xml")),
+		fmt.Sprintf("--jsonfile=%s", path.Join(results, "test.json")),
+		"--format=standard-verbose",
+		"--",
+		tagsArg,
+		fmt.Sprintf("-coverprofile=%s", path.Join(results, "coverage.out")),
+		"-covermode=atomic",
+		"$packages",
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf("mkdir -p %s", results),
+		fmt.Sprintf("go list %s %s > %s", tagsArg, strings.Join(patterns, " "), packages),
+		fmt.Sprintf("packages=$(%s %s)", shards.SelectCommand(shard), packages),
+		fmt.Sprintf(`if [ -z "$packages" ]; then echo 0 > %s; exit 0; fi`, exitCode),
+		fmt.Sprintf("{ %s > %s 2>&1; echo $? > %s; }", strings.Join(args, " "), path.Join(results, "test.log"), exitCode),
+	}, " && ")
+}
+
+// Test runs 'go test' in the builder for the packages of the shard and returns the results directory.
+func Test(builder *dagger.Container, opts *TestOpts) *dagger.Directory {
+	// gotestsum is installed for the platform of the container, which is the distribution that the tests run on.
+	return builder.
+		WithExec([]string{"/bin/sh", "-c", fmt.Sprintf("CGO_ENABLED=0 go install gotest.tools/gotestsum@%s", GotestsumVersion)}).
+		WithExec([]string{"/bin/sh", "-c", GoTestCommand(TestResultsDir, opts.Tags, opts.Packages, opts.Shard)}).
+		Directory(TestResultsDir)
+}
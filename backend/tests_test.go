@@ -0,0 +1,23 @@
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/shards"
+)
+
+func TestGoTestCommand(t *testing.T) {
+	cmd := backend.GoTestCommand("/tmp/results", []string{"osusergo", "pro"}, []string{"./pkg/api", "./pkg/services/..."}, shards.Shard{Index: 2, Total: 3})
+	expect := "mkdir -p /tmp/results && " +
+		"go list -tags=osusergo,pro ./pkg/api ./pkg/services/... > /tmp/results/packages.txt && " +
+		"packages=$(awk '(NR - 1) % 3 == 1' /tmp/results/packages.txt) && " +
+		`if [ -z "$packages" ]; then echo 0 > /tmp/results/exit-code; exit 0; fi && ` +
+		"{ gotestsum --junitfile=/tmp/results/junit.xml --jsonfile=/tmp/results/test.json --format=standard-verbose -- " +
+		"-tags=osusergo,pro -coverprofile=/tmp/results/coverage.out -covermode=atomic $packages " +
+		"> /tmp/results/test.log 2>&1; echo $? > /tmp/results/exit-code; }"
+
+	if cmd != expect {
+		t.Errorf("expected\n%s\nbut got\n%s", expect, cmd)
+	}
+}
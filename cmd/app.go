@@ -2,6 +2,8 @@ package main
 
 import (
 	"github.com/grafana/grafana-build/artifacts"
+	"github.com/grafana/grafana-build/cmd/flags"
+	"github.com/grafana/grafana-build/logging"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,6 +30,29 @@ func (c *CLI) App() *cli.App {
 	return &cli.App{
 		Name:  "grafana-build",
 		Usage: "A build tool for Grafana",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "trace-file",
+				Usage:   "Writes the spans of the command as OTLP JSON to this file. Use the 'report' command to view them",
+				EnvVars: []string{"GRAFANA_BUILD_TRACE_FILE"},
+			},
+			&cli.BoolFlag{
+				Name:    "trace-file-overwrite",
+				Usage:   "Replaces the '--trace-file' if it already exists. Without it, commands fail instead of replacing the trace of an earlier run",
+				EnvVars: []string{"GRAFANA_BUILD_TRACE_FILE_OVERWRITE"},
+			},
+			&flags.ChoiceFlag{
+				Name:    "log-format",
+				Usage:   "Format of the logs written to stderr",
+				Value:   logging.FormatText,
+				Choices: logging.Formats,
+			},
+			&cli.StringFlag{
+				Name:    "events",
+				Usage:   "Writes lifecycle events of each artifact, like 'artifact-exported' or 'artifact-failed', as newline-delimited JSON to this file",
+				EnvVars: []string{"GRAFANA_BUILD_EVENTS"},
+			},
+		},
 		Commands: []*cli.Command{
 			artifactsCommand,
 
@@ -52,6 +77,8 @@ func (c *CLI) App() *cli.App {
 				},
 			},
 			GCOMCommand,
+			ReportCommand,
+			DiffCommand,
 		},
 	}
 }
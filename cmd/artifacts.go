@@ -5,14 +5,19 @@ import (
 )
 
 var Artifacts = map[string]artifacts.Initializer{
-	"backend":   artifacts.BackendInitializer,
-	"frontend":  artifacts.FrontendInitializer,
-	"npm":       artifacts.NPMPackagesInitializer,
-	"targz":     artifacts.TargzInitializer,
-	"zip":       artifacts.ZipInitializer,
-	"deb":       artifacts.DebInitializer,
-	"rpm":       artifacts.RPMInitializer,
-	"docker":    artifacts.DockerInitializer,
-	"storybook": artifacts.StorybookInitializer,
-	"exe":       artifacts.ExeInitializer,
+	"backend":        artifacts.BackendInitializer,
+	"backend-test":   artifacts.BackendTestInitializer,
+	"coverage":       artifacts.CoverageInitializer,
+	"frontend":       artifacts.FrontendInitializer,
+	"frontend-test":  artifacts.FrontendTestInitializer,
+	"frontend-lint":  artifacts.FrontendLintInitializer,
+	"frontend-stats": artifacts.FrontendStatsInitializer,
+	"npm":            artifacts.NPMPackagesInitializer,
+	"targz":          artifacts.TargzInitializer,
+	"zip":            artifacts.ZipInitializer,
+	"deb":            artifacts.DebInitializer,
+	"rpm":            artifacts.RPMInitializer,
+	"docker":         artifacts.DockerInitializer,
+	"storybook":      artifacts.StorybookInitializer,
+	"exe":            artifacts.ExeInitializer,
 }
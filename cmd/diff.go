@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/cmd/flags"
+	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/diff"
+	"github.com/urfave/cli/v2"
+)
+
+var DiffCommand = &cli.Command{
+	Name:        "diff",
+	Action:      Diff,
+	Usage:       "Compares the files and the package metadata of two tar.gz, zip, deb, rpm, or docker artifacts",
+	ArgsUsage:   "<old> <new>",
+	Description: "Reports the files that were added, removed, or changed with their size and permissions, and the changes to the deb control fields, rpm headers, or image config. Artifacts can be local paths or 'gs://' and 's3://' URLs",
+	Flags: JoinFlagsWithDefault(
+		[]cli.Flag{
+			&flags.ChoiceFlag{
+				Name:    "format",
+				Usage:   "Format of the differences",
+				Value:   "text",
+				Choices: []string{"text", "json"},
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Path to write the differences to. If not set, they are written to stdout",
+			},
+			&cli.BoolFlag{
+				Name:  "exit-code",
+				Usage: "Exit with 1 if the artifacts are different, like 'git diff --exit-code'",
+			},
+		},
+		GCPFlags,
+		S3Flags,
+	),
+}
+
+// localPath returns the path of the artifact on the local filesystem, or false if it has to be downloaded.
+func localPath(artifact string) (string, bool) {
+	u, err := url.Parse(artifact)
+	if err != nil {
+		return artifact, true
+	}
+
+	switch u.Scheme {
+	case "":
+		return artifact, true
+	case "file", "fs":
+		return u.Host + u.Path, true
+	}
+
+	return "", false
+}
+
+// downloadArtifacts downloads the remote artifacts into dir and returns their local paths, keeping the file names so that their type can be determined.
+func downloadArtifacts(ctx context.Context, c *cli.Context, artifacts []string, dir string) ([]string, error) {
+	client, err := dagger.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	files, err := containers.GetPackages(ctx, client, &containers.PackageInputOpts{Packages: artifacts}, containers.GCPOptsFromFlags(c), containers.S3OptsFromFlags(c))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		// Both artifacts can have the same name, so each one is downloaded into its own folder.
+		paths[i] = filepath.Join(dir, fmt.Sprint(i), filepath.Base(artifacts[i]))
+		if _, err := f.Export(ctx, paths[i]); err != nil {
+			return nil, fmt.Errorf("error downloading '%s': %w", artifacts[i], err)
+		}
+	}
+
+	return paths, nil
+}
+
+func Diff(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return errors.New("expected 2 arguments: the old and the new artifact")
+	}
+	artifacts := c.Args().Slice()
+
+	var (
+		paths  = make([]string, len(artifacts))
+		remote []string
+	)
+	for i, a := range artifacts {
+		if p, ok := localPath(a); ok {
+			paths[i] = p
+			continue
+		}
+		remote = append(remote, a)
+	}
+
+	if len(remote) != 0 {
+		dir, err := os.MkdirTemp("", "grafana-build-diff")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		downloaded, err := downloadArtifacts(c.Context, c, remote, dir)
+		if err != nil {
+			return err
+		}
+		for i := range paths {
+			if paths[i] == "" {
+				paths[i], downloaded = downloaded[0], downloaded[1:]
+			}
+		}
+	}
+
+	before, err := diff.ReadFile(paths[0])
+	if err != nil {
+		return err
+	}
+	after, err := diff.ReadFile(paths[1])
+	if err != nil {
+		return err
+	}
+	if before.Type != after.Type {
+		return fmt.Errorf("can't compare a %s artifact with a %s artifact", before.Type, after.Type)
+	}
+
+	r := diff.Compare(artifacts[0], before, artifacts[1], after)
+
+	var w io.Writer = c.App.Writer
+	if path := c.String("output"); path != "" {
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+
+	if c.String("format") == "json" {
+		err = diff.WriteJSON(w, r)
+	} else {
+		err = diff.WriteText(w, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("exit-code") && !r.Empty() {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
@@ -14,6 +14,8 @@ var DockerPublishCommand = &cli.Command{
 		DockerFlags,
 		DockerPublishFlags,
 		GCPFlags,
+		S3Flags,
 		ConcurrencyFlags,
+		PublishRetryFlags,
 	),
 }
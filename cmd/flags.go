@@ -3,6 +3,8 @@ package main
 import (
 	"github.com/grafana/grafana-build/arguments"
 	"github.com/grafana/grafana-build/cmd/flags"
+	"github.com/grafana/grafana-build/gcom"
+	"github.com/grafana/grafana-build/retry"
 	"github.com/urfave/cli/v2"
 )
 
@@ -34,6 +36,39 @@ var GCPFlags = []cli.Flag{
 	},
 }
 
+// S3Flags are used in commands that upload to or download from S3 or an S3-compatible object storage using the AWS CLI
+var S3Flags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "s3-access-key-id",
+		Usage:   "The access key ID used to authenticate with S3. If not provided or is empty, then $HOME/.aws will be mounted in the container",
+		EnvVars: []string{"AWS_ACCESS_KEY_ID"},
+	},
+	&cli.StringFlag{
+		Name:    "s3-secret-access-key",
+		Usage:   "The secret access key used to authenticate with S3",
+		EnvVars: []string{"AWS_SECRET_ACCESS_KEY"},
+	},
+	&cli.StringFlag{
+		Name:    "s3-session-token",
+		Usage:   "The session token used to authenticate with S3 when using temporary credentials",
+		EnvVars: []string{"AWS_SESSION_TOKEN"},
+	},
+	&cli.StringFlag{
+		Name:    "s3-region",
+		Usage:   "The region of the S3 bucket",
+		EnvVars: []string{"AWS_REGION", "AWS_DEFAULT_REGION"},
+	},
+	&cli.StringFlag{
+		Name:    "s3-endpoint",
+		Usage:   "Overrides the S3 endpoint URL to use an S3-compatible object storage like MinIO (example: 'http://minio:9000')",
+		EnvVars: []string{"AWS_ENDPOINT_URL"},
+	},
+	&cli.BoolFlag{
+		Name:  "s3-path-style",
+		Usage: "Use path-style addressing ('{endpoint}/{bucket}/{key}') instead of virtual-hosted-style addressing. This is usually required when using --s3-endpoint",
+	},
+}
+
 // NPMFlags are used in commands that need to authenticate with package registries to publish NPM packages
 var NPMFlags = []cli.Flag{
 	&cli.StringFlag{
@@ -131,14 +166,10 @@ var GrafanaFlags = []cli.Flag{
 }
 
 // DockerFlags are used when producing docker images.
-var DockerFlags = []cli.Flag{
+var DockerFlags = append([]cli.Flag{
 	arguments.DockerRegistryFlag,
-	arguments.AlpineImageFlag,
-	arguments.UbuntuImageFlag,
-	arguments.TagFormatFlag,
-	arguments.UbuntuTagFormatFlag,
 	arguments.DockerOrgFlag,
-}
+}, arguments.DockerVariantFlags()...)
 
 var DockerPublishFlags = []cli.Flag{
 	&cli.StringFlag{
@@ -169,6 +200,9 @@ var FlagDistros = &cli.StringSliceFlag{
 
 var ConcurrencyFlags = flags.ConcurrencyFlags
 
+// PublishRetryFlags are used for commands that publish artifacts and retry when publishing fails with a transient error.
+var PublishRetryFlags = flags.RetryFlags(retry.PhasePublish)
+
 // PackageFlags are flags that are used when building packages or similar artifacts (like binaries) for different distributions
 // from the grafana source code.
 var PackageFlags = []cli.Flag{
@@ -224,6 +258,7 @@ var ProImageFlags = []cli.Flag{
 	},
 }
 
+// GCOMFlags are used in commands that make requests to grafana.com
 var GCOMFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:  "api-url",
@@ -235,6 +270,14 @@ var GCOMFlags = []cli.Flag{
 		Usage:    "API Key used in requests to grafana.com",
 		Required: true,
 	},
+	&cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the requests that would be sent to grafana.com instead of sending them",
+	},
+}
+
+// GCOMPublishFlags are used in commands that publish versions and packages to grafana.com
+var GCOMPublishFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:     "download-url",
 		Usage:    "URL used to download packages from grafana.com",
@@ -248,6 +291,33 @@ var GCOMFlags = []cli.Flag{
 		Name:  "nightly",
 		Usage: "Use when publishing a nightly version",
 	},
+	&cli.StringFlag{
+		Name:  "whats-new-url",
+		Usage: "Go template of the 'What's new' URL of the version. Available values are '.version', '.version_base', '.major', '.minor', and '.patch'",
+		Value: gcom.DefaultWhatsNewURLFormat,
+	},
+	&cli.StringFlag{
+		Name:  "release-notes-url",
+		Usage: "Go template of the release notes URL of the version. Uses the same values as --whats-new-url",
+		Value: gcom.DefaultReleaseNotesURLFormat,
+	},
+	&cli.StringFlag{
+		Name:  "package-mappings",
+		Usage: "Path to a JSON list of mappings from package extension, os, and arch to the grafana.com 'os' and 'arch'. These take precedence over the default mappings",
+	},
+	&cli.StringFlag{
+		Name:  "manifest",
+		Usage: "Path to a JSON release manifest with the version, os, arch, and checksum of each package. Packages that are not in the manifest use the metadata in their file name",
+	},
+}
+
+// ConfirmFlags are used in commands that ask for confirmation before making destructive changes
+var ConfirmFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Do not ask for confirmation before making destructive changes",
+	},
 }
 
 // JoinFlags combines several slices of flags into one slice of flags.
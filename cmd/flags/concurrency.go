@@ -1,8 +1,10 @@
 package flags
 
 import (
+	"fmt"
 	"runtime"
 
+	"github.com/grafana/grafana-build/pipeline"
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,3 +16,17 @@ var ConcurrencyFlags = []cli.Flag{
 		Value:       int64(runtime.GOMAXPROCS(0)),
 	},
 }
+
+// ResourceFlags returns the flags that limit how much of each resource class the running actions can use at the same time, like '--parallel-frontend'.
+func ResourceFlags() []cli.Flag {
+	f := make([]cli.Flag, len(pipeline.ResourceClasses))
+	for i, class := range pipeline.ResourceClasses {
+		f[i] = &cli.Int64Flag{
+			Name:        pipeline.ParallelFlag(class),
+			Usage:       fmt.Sprintf("The total weight of '%s' actions that can run at the same time, in addition to the '--parallel' limit", class),
+			DefaultText: "0, which means only '--parallel' applies",
+		}
+	}
+
+	return f
+}
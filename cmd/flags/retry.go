@@ -0,0 +1,40 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-build/retry"
+	"github.com/urfave/cli/v2"
+)
+
+// RetryFlags returns the flags that set the retry policy of each of the phases.
+func RetryFlags(phases ...retry.Phase) []cli.Flag {
+	f := make([]cli.Flag, 0, len(phases)+2)
+	for _, phase := range phases {
+		f = append(f, &cli.Int64Flag{
+			Name:  retry.AttemptsFlag(phase),
+			Usage: fmt.Sprintf("The number of times to attempt the '%s' phase when it fails with a transient error, like a network timeout", phase),
+			Value: retry.DefaultAttempts[phase],
+		})
+	}
+
+	return append(f,
+		&cli.DurationFlag{
+			Name:  "retry-backoff",
+			Usage: "Time to wait before the first retry. The wait time doubles after each attempt",
+			Value: retry.DefaultBackoff,
+		},
+		&cli.DurationFlag{
+			Name:  "retry-max-backoff",
+			Usage: "The longest time to wait between retries",
+			Value: retry.DefaultMaxBackoff,
+		},
+	)
+}
+
+var FailureFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "keep-going",
+		Usage: "Keep building, exporting, and verifying the other artifacts when one fails, and show all of the failures at the end",
+	},
+}
@@ -0,0 +1,33 @@
+package flags
+
+import (
+	"github.com/grafana/grafana-build/scan"
+	"github.com/urfave/cli/v2"
+)
+
+var ScanFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "scan",
+		Usage: "If true, then the artifacts that support it (docker images and tarballs) are scanned for vulnerabilities and a report is exported next to each artifact",
+		Value: false,
+	},
+	&cli.StringFlag{
+		Name:  "scan-severity",
+		Usage: "The scan fails if a vulnerability with this severity or higher is found (UNKNOWN, LOW, MEDIUM, HIGH, CRITICAL)",
+		Value: "HIGH",
+	},
+	&cli.StringFlag{
+		Name:  "scan-format",
+		Usage: "The format of the exported vulnerability report (sarif, json)",
+		Value: string(scan.FormatSARIF),
+	},
+	&cli.StringFlag{
+		Name:  "scan-db",
+		Usage: "Path to a local trivy cache directory that contains the vulnerability database ('db/trivy.db'). If set, the database is not downloaded or updated, which allows scanning without network access",
+	},
+	&cli.StringFlag{
+		Name:  "scan-image",
+		Usage: "The trivy docker image used to scan artifacts",
+		Value: scan.TrivyImage,
+	},
+}
@@ -0,0 +1,23 @@
+package flags
+
+import "github.com/urfave/cli/v2"
+
+var SummaryFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "summary-markdown",
+		Usage: "Appends the build summary as a Markdown table to this file, for example '$GITHUB_STEP_SUMMARY'",
+	},
+	&cli.StringFlag{
+		Name:  "summary-manifest",
+		Usage: "Writes the build summary as JSON to this file, so that it can be used as the '--previous-manifest' of a later build",
+	},
+	&cli.StringFlag{
+		Name:  "previous-manifest",
+		Usage: "Path to the '--summary-manifest' of a previous build. Artifacts that grew more than '--size-threshold' percent are flagged as size regressions",
+	},
+	&cli.Float64Flag{
+		Name:  "size-threshold",
+		Usage: "The percentage that an artifact can grow compared to the '--previous-manifest' before it is flagged as a size regression",
+		Value: 10,
+	},
+}
@@ -7,5 +7,11 @@ import (
 var GCOMCommand = &cli.Command{
 	Name:        "gcom",
 	Description: "Executes requests to grafana.com",
-	Subcommands: []*cli.Command{GCOMPublishCommand},
+	Subcommands: []*cli.Command{
+		GCOMPublishCommand,
+		GCOMListCommand,
+		GCOMUpdateCommand,
+		GCOMPromoteCommand,
+		GCOMDeleteCommand,
+	},
 }
@@ -11,6 +11,7 @@ var GCOMPublishCommand = &cli.Command{
 	Description: "Publishes a grafana.tar.gz (ideally one built using the 'package' command) to grafana.com (--destination will be the download path)",
 	Flags: JoinFlagsWithDefault(
 		GCOMFlags,
+		GCOMPublishFlags,
 		PackageInputFlags,
 		PublishFlags,
 		ConcurrencyFlags,
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grafana/grafana-build/gcom"
+	"github.com/urfave/cli/v2"
+)
+
+var FlagGCOMVersion = &cli.StringFlag{
+	Name:     "version",
+	Usage:    "The Grafana version on grafana.com, like '10.2.0' or '10.2.0-beta1'",
+	Required: true,
+}
+
+var GCOMListCommand = &cli.Command{
+	Name:        "list",
+	Action:      GCOMList,
+	Description: "Lists the versions on grafana.com, or the packages of a version if --version is set",
+	Flags: JoinFlagsWithDefault(
+		GCOMFlags,
+		[]cli.Flag{
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "If set, lists the packages of this version",
+			},
+		},
+	),
+}
+
+var GCOMUpdateCommand = &cli.Command{
+	Name:        "update",
+	Action:      GCOMUpdate,
+	Description: "Updates the stable, beta, and nightly flags of a version on grafana.com. Flags that are not provided are not changed",
+	Flags: JoinFlagsWithDefault(
+		GCOMFlags,
+		[]cli.Flag{
+			FlagGCOMVersion,
+			&cli.BoolFlag{Name: "stable", Usage: "Marks the version as stable"},
+			&cli.BoolFlag{Name: "beta", Usage: "Marks the version as a beta"},
+			&cli.BoolFlag{Name: "nightly", Usage: "Marks the version as a nightly"},
+		},
+	),
+}
+
+var GCOMPromoteCommand = &cli.Command{
+	Name:        "promote",
+	Action:      GCOMPromote,
+	Description: "Promotes a beta or nightly version on grafana.com to stable",
+	Flags: JoinFlagsWithDefault(
+		GCOMFlags,
+		ConfirmFlags,
+		[]cli.Flag{
+			FlagGCOMVersion,
+		},
+	),
+}
+
+var GCOMDeleteCommand = &cli.Command{
+	Name:        "delete",
+	Action:      GCOMDelete,
+	Description: "Deletes a version, or a single package of a version if --os and --arch are set, from grafana.com",
+	Flags: JoinFlagsWithDefault(
+		GCOMFlags,
+		ConfirmFlags,
+		[]cli.Flag{
+			FlagGCOMVersion,
+			&cli.StringFlag{Name: "os", Usage: "The OS of the package to delete, like 'linux', 'deb', or 'win-installer'"},
+			&cli.StringFlag{Name: "arch", Usage: "The architecture of the package to delete, like 'amd64' or 'armv7'"},
+		},
+	),
+}
+
+func gcomClient(c *cli.Context) (*gcom.Client, error) {
+	opts, err := gcom.GCOMOptsFromFlags(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcom.NewClient(opts), nil
+}
+
+// confirm asks the user to confirm the action unless '--yes' or '--dry-run' is set.
+func confirm(c *cli.Context, in io.Reader, action string) error {
+	if c.Bool("yes") || c.Bool("dry-run") {
+		return nil
+	}
+
+	fmt.Fprintf(c.App.ErrWriter, "%s. Continue? [y/N] ", action)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+
+	return errors.New("aborted")
+}
+
+func releaseType(v gcom.GCOMVersion) string {
+	switch {
+	case v.Stable:
+		return "stable"
+	case v.Beta:
+		return "beta"
+	case v.Nightly:
+		return "nightly"
+	}
+
+	return "-"
+}
+
+func GCOMList(c *cli.Context) error {
+	client, err := gcomClient(c)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.App.Writer, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	version := c.String("version")
+	if version == "" {
+		versions, err := client.ListVersions(c.Context)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, "VERSION\tTYPE\tRELEASE DATE")
+		for _, v := range versions {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Version, releaseType(v), v.ReleaseDate)
+		}
+
+		return nil
+	}
+
+	v, err := client.GetVersion(c.Context, version)
+	if err != nil {
+		return err
+	}
+
+	packages, err := client.ListPackages(c.Context, version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "VERSION\t%s\nTYPE\t%s\nRELEASE DATE\t%s\n\n", v.Version, releaseType(*v), v.ReleaseDate)
+	fmt.Fprintln(w, "OS\tARCH\tSHA256\tURL")
+	for _, p := range packages {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.OS, p.Arch, p.Sha256, p.URL)
+	}
+
+	return nil
+}
+
+func GCOMUpdate(c *cli.Context) error {
+	client, err := gcomClient(c)
+	if err != nil {
+		return err
+	}
+
+	v, err := client.GetVersion(c.Context, c.String("version"))
+	if err != nil {
+		return err
+	}
+
+	payload := v.GCOMVersionPayload
+	if c.IsSet("stable") {
+		payload.Stable = c.Bool("stable")
+	}
+	if c.IsSet("beta") {
+		payload.Beta = c.Bool("beta")
+	}
+	if c.IsSet("nightly") {
+		payload.Nightly = c.Bool("nightly")
+	}
+
+	updated, err := client.UpdateVersion(c.Context, &payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.App.Writer, "%s: %s\n", updated.Version, releaseType(*updated))
+	return nil
+}
+
+func GCOMPromote(c *cli.Context) error {
+	client, err := gcomClient(c)
+	if err != nil {
+		return err
+	}
+
+	v, err := client.GetVersion(c.Context, c.String("version"))
+	if err != nil {
+		return err
+	}
+
+	if v.Stable {
+		return fmt.Errorf("version '%s' is already stable", v.Version)
+	}
+
+	if err := confirm(c, os.Stdin, fmt.Sprintf("Promoting %s version '%s' to stable", releaseType(*v), v.Version)); err != nil {
+		return err
+	}
+
+	updated, err := client.UpdateVersion(c.Context, gcom.Promote(v.GCOMVersionPayload))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.App.Writer, "%s: %s\n", updated.Version, releaseType(*updated))
+	return nil
+}
+
+func GCOMDelete(c *cli.Context) error {
+	client, err := gcomClient(c)
+	if err != nil {
+		return err
+	}
+
+	var (
+		version = c.String("version")
+		goos    = c.String("os")
+		arch    = c.String("arch")
+	)
+
+	if (goos == "") != (arch == "") {
+		return errors.New("--os and --arch must be set together")
+	}
+
+	if goos == "" {
+		if err := confirm(c, os.Stdin, fmt.Sprintf("Deleting version '%s' and all of its packages", version)); err != nil {
+			return err
+		}
+		if err := client.DeleteVersion(c.Context, version); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.App.Writer, "deleted %s\n", version)
+		return nil
+	}
+
+	if err := confirm(c, os.Stdin, fmt.Sprintf("Deleting the %s/%s package of version '%s'", goos, arch, version)); err != nil {
+		return err
+	}
+	if err := client.DeletePackage(c.Context, version, goos, arch); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.App.Writer, "deleted %s %s/%s\n", version, goos, arch)
+	return nil
+}
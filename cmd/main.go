@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"sync"
 
 	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/events"
+	"github.com/grafana/grafana-build/logging"
 	"github.com/grafana/grafana-build/otel"
 	"github.com/grafana/grafana-build/pipelines"
 	"github.com/urfave/cli/v2"
@@ -21,6 +25,7 @@ func PipelineActionWithPackageInput(pf pipelines.PipelineFuncWithPackageInput) c
 		)
 		ctx, span := otel.Tracer("grafana-build").Start(ctx, fmt.Sprintf("pipeline-%s", c.Command.Name))
 		defer span.End()
+		logging.SetVerbose(c.Bool("verbose"))
 		if c.Bool("verbose") {
 			opts = append(opts, dagger.WithLogOutput(os.Stderr))
 		}
@@ -62,16 +67,67 @@ func init() {
 }
 
 func main() {
-	ctx := context.Background()
-	shutdown := otel.Setup(ctx)
-	app := globalCLI.App()
+	var (
+		ctx      = context.Background()
+		app      = globalCLI.App()
+		once     = &sync.Once{}
+		shutdown = func(context.Context) error { return nil }
+		closers  = []io.Closer{}
+	)
 
-	if err := app.RunContext(otel.FindParentTrace(ctx), os.Args); err != nil {
-		if err := shutdown(context.Background()); err != nil {
-			log.Printf("Failed to shutdown tracer: %s", err.Error())
+	// Logging, tracing, and events are set up after the flags are parsed so that the '--log-format', '--trace-file', and '--events' flags can be used.
+	flush := func() {
+		once.Do(func() {
+			if err := shutdown(context.Background()); err != nil {
+				slog.Error("Failed to shutdown tracer", "error", err)
+			}
+			for _, c := range closers {
+				c.Close()
+			}
+		})
+	}
+	app.Before = func(c *cli.Context) error {
+		logger, err := logging.New(os.Stderr, c.String("log-format"))
+		if err != nil {
+			return err
 		}
-		if err := shutdown(context.Background()); err != nil {
-			log.Printf("Failed to shutdown tracer: %s", err.Error())
+		slog.SetDefault(logger)
+
+		if path := c.String("events"); path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("error creating events file: %w", err)
+			}
+			closers = append(closers, f)
+			events.SetDefault(events.NewWriter(f))
 		}
+
+		// The 'report' command reads a trace file, which is often the one in GRAFANA_BUILD_TRACE_FILE, so it isn't traced.
+		if c.Args().First() == ReportCommand.Name {
+			return nil
+		}
+
+		traceFile, overwrite := c.String("trace-file"), c.Bool("trace-file-overwrite")
+		if traceFile != "" && !overwrite {
+			if _, err := os.Stat(traceFile); err == nil {
+				return fmt.Errorf("trace file '%s' already exists; move it or use '--trace-file-overwrite' to replace it", traceFile)
+			}
+		}
+
+		shutdown = otel.Setup(c.Context, traceFile, overwrite)
+		c.Context = otel.FindParentTrace(c.Context)
+		return nil
+	}
+	// Commands that return a cli.ExitCoder exit the process before RunContext returns, so the spans have to be flushed first.
+	app.ExitErrHandler = func(c *cli.Context, err error) {
+		flush()
+		cli.HandleExitCoder(err)
+	}
+
+	err := app.RunContext(ctx, os.Args)
+	flush()
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 }
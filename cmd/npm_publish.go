@@ -13,6 +13,8 @@ var PublishNPMCommand = &cli.Command{
 		PackageInputFlags,
 		NPMFlags,
 		GCPFlags,
+		S3Flags,
 		ConcurrencyFlags,
+		PublishRetryFlags,
 	),
 }
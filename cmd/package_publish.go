@@ -13,6 +13,8 @@ var PackagePublishCommand = &cli.Command{
 		PackageInputFlags,
 		PublishFlags,
 		GCPFlags,
+		S3Flags,
 		ConcurrencyFlags,
+		PublishRetryFlags,
 	),
 }
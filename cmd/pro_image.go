@@ -9,5 +9,5 @@ var ProImageCommand = &cli.Command{
 	Name:        "pro-image",
 	Action:      PipelineActionWithPackageInput(pipelines.ProImage),
 	Description: "Creates a hosted grafana pro image",
-	Flags:       JoinFlagsWithDefault(ProImageFlags, GCPFlags, PackageInputFlags),
+	Flags:       JoinFlagsWithDefault(ProImageFlags, GCPFlags, S3Flags, PackageInputFlags),
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/grafana/grafana-build/cmd/flags"
+	"github.com/grafana/grafana-build/report"
+	"github.com/urfave/cli/v2"
+)
+
+var ReportCommand = &cli.Command{
+	Name:        "report",
+	Action:      Report,
+	Usage:       "Creates a timeline of a build from a file written with '--trace-file'",
+	Description: "Reads the spans in a trace file and shows the critical path, the duration of every artifact action, and how many actions ran in parallel",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Aliases:  []string{"f"},
+			Usage:    "Path to the trace file written with '--trace-file'",
+			Required: true,
+		},
+		&flags.ChoiceFlag{
+			Name:    "format",
+			Usage:   "Format of the report",
+			Value:   "text",
+			Choices: []string{"text", "html"},
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Path to write the report to. If not set, the report is written to stdout",
+		},
+	},
+}
+
+func Report(c *cli.Context) error {
+	f, err := os.Open(c.String("file"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	spans, err := report.ReadSpans(f)
+	if err != nil {
+		return err
+	}
+
+	r, err := report.New(spans)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = c.App.Writer
+	if path := c.String("output"); path != "" {
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+
+	if c.String("format") == "html" {
+		return report.WriteHTML(w, r)
+	}
+
+	return report.WriteText(w, r)
+}
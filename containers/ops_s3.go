@@ -0,0 +1,28 @@
+package containers
+
+import "github.com/grafana/grafana-build/cliutil"
+
+// S3Opts are options used when uploading to or downloading from S3 or an S3-compatible object storage like MinIO.
+type S3Opts struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+
+	// Endpoint overrides the S3 endpoint URL, for S3-compatible services like MinIO. Example: 'http://localhost:9000'.
+	Endpoint string
+
+	// PathStyle forces path-style addressing ('http://endpoint/bucket/key'), which is usually required by S3-compatible services.
+	PathStyle bool
+}
+
+func S3OptsFromFlags(c cliutil.CLIContext) *S3Opts {
+	return &S3Opts{
+		AccessKeyID:     c.String("s3-access-key-id"),
+		SecretAccessKey: c.String("s3-secret-access-key"),
+		SessionToken:    c.String("s3-session-token"),
+		Region:          c.String("s3-region"),
+		Endpoint:        c.String("s3-endpoint"),
+		PathStyle:       c.Bool("s3-path-style"),
+	}
+}
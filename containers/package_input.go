@@ -2,10 +2,6 @@ package containers
 
 import (
 	"context"
-	"fmt"
-	"net/url"
-	"path/filepath"
-	"strings"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/cliutil"
@@ -25,35 +21,24 @@ func PackageInputOptsFromFlags(c cliutil.CLIContext) *PackageInputOpts {
 	}
 }
 
-// GetPackage uses the PackageInputOpts to get a Grafana package, either from the local filesystem (if the package is of type 'file://...')
-// or Google Cloud Storage if the package is a 'gs://' URL.
-func GetPackages(ctx context.Context, d *dagger.Client, packageOpts *PackageInputOpts, gcpOpts *GCPOpts) ([]*dagger.File, error) {
+// GetPackage uses the PackageInputOpts to get a Grafana package from the Storage that is registered for the scheme of the package URL.
+// For example, the local filesystem for 'file://...' URLs, Google Cloud Storage for 'gs://' URLs, or S3 for 's3://' URLs.
+func GetPackages(ctx context.Context, d *dagger.Client, packageOpts *PackageInputOpts, gcpOpts *GCPOpts, s3Opts *S3Opts) ([]*dagger.File, error) {
+	storageOpts := &StorageOpts{
+		GCPOpts: gcpOpts,
+		S3Opts:  s3Opts,
+	}
+
 	files := make([]*dagger.File, len(packageOpts.Packages))
 	for i, pkg := range packageOpts.Packages {
-		u, err := url.Parse(pkg)
+		storage, err := StorageForURL(pkg, storageOpts)
 		if err != nil {
 			return nil, err
 		}
 
-		var file *dagger.File
-		switch u.Scheme {
-		case "file", "fs":
-			p := strings.TrimPrefix(u.String(), u.Scheme+"://")
-			f, err := getLocalPackage(ctx, d, p)
-			if err != nil {
-				return nil, err
-			}
-
-			file = f
-		case "gs":
-			f, err := getGCSPackage(ctx, d, gcpOpts, u.String())
-			if err != nil {
-				return nil, err
-			}
-
-			file = f
-		default:
-			return nil, fmt.Errorf("%w: %s", ErrorUnrecognizedScheme, u.Scheme)
+		file, err := storage.Download(ctx, d, pkg)
+		if err != nil {
+			return nil, err
 		}
 
 		files[i] = file
@@ -61,13 +46,3 @@ func GetPackages(ctx context.Context, d *dagger.Client, packageOpts *PackageInpu
 
 	return files, nil
 }
-
-func getLocalPackage(ctx context.Context, d *dagger.Client, file string) (*dagger.File, error) {
-	// pending https://github.com/dagger/dagger/issues/4745
-	return d.Host().Directory(filepath.Dir(file)).File(filepath.Base(file)), nil
-}
-
-func getGCSPackage(ctx context.Context, d *dagger.Client, opts *GCPOpts, gcsURL string) (*dagger.File, error) {
-	auth := GCSAuth(d, opts)
-	return GCSDownloadFile(d, GoogleCloudImage, auth, gcsURL)
-}
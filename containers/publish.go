@@ -3,10 +3,7 @@ package containers
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
-	"net/url"
-	"strings"
+	"log/slog"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/cliutil"
@@ -20,6 +17,7 @@ type PublishOpts struct {
 	// * '/tmp/package.tar.gz'
 	// * 'file:///tmp/package.tar.gz'
 	// * 'gcs://bucket/package.tar.gz'
+	// * 's3://bucket/package.tar.gz'
 	Destination string
 
 	// Checksum defines if the PublishFile function should also produce / publish a checksum of the given `*dagger.File'
@@ -35,32 +33,11 @@ func PublishOptsFromFlags(c cliutil.CLIContext) *PublishOpts {
 
 var ErrorUnrecognizedScheme = errors.New("unrecognized scheme")
 
-func publishLocalFile(ctx context.Context, file *dagger.File, dst string) error {
-	if _, err := file.Export(ctx, dst); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func publishGCSFile(ctx context.Context, d *dagger.Client, file *dagger.File, opts *GCPOpts, destination string) error {
-	auth := GCSAuth(d, opts)
-	uploader, err := GCSUploadFile(d, GoogleCloudImage, auth, file, destination)
-	if err != nil {
-		return err
-	}
-
-	if _, err := ExitError(ctx, uploader); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 type PublishFileOpts struct {
 	File        *dagger.File
 	PublishOpts *PublishOpts
 	GCPOpts     *GCPOpts
+	S3Opts      *S3Opts
 	Destination string
 }
 
@@ -72,6 +49,7 @@ func PublishFile(ctx context.Context, d *dagger.Client, opts *PublishFileOpts) (
 		file        = opts.File
 		publishOpts = opts.PublishOpts
 		gcpOpts     = opts.GCPOpts
+		s3Opts      = opts.S3Opts
 	)
 	// a map of 'destination' to 'file'
 	files := map[string]*dagger.File{
@@ -79,33 +57,24 @@ func PublishFile(ctx context.Context, d *dagger.Client, opts *PublishFileOpts) (
 	}
 	if publishOpts.Checksum {
 		name := destination + ".sha256"
-		log.Println("Checksum is enabled, creating checksum", name)
+		slog.Info("Checksum is enabled, creating checksum", "file", name)
 		files[name] = Sha256(d, file)
 	}
 
+	storageOpts := &StorageOpts{
+		GCPOpts: gcpOpts,
+		S3Opts:  s3Opts,
+	}
+
 	for dst, f := range files {
-		log.Println("Publishing", dst)
-		u, err := url.Parse(dst)
+		slog.Info("Publishing", "destination", dst)
+		storage, err := StorageForURL(dst, storageOpts)
 		if err != nil {
-			// If the destination URL is not a URL then we can assume that it's just a filepath.
-			if err := publishLocalFile(ctx, f, dst); err != nil {
-				return nil, err
-			}
-			continue
+			return nil, err
 		}
 
-		switch u.Scheme {
-		case "file", "fs":
-			dst := strings.TrimPrefix(u.String(), u.Scheme+"://")
-			if err := publishLocalFile(ctx, f, dst); err != nil {
-				return nil, err
-			}
-		case "gs":
-			if err := publishGCSFile(ctx, d, f, gcpOpts, dst); err != nil {
-				return nil, err
-			}
-		default:
-			return nil, fmt.Errorf("%w: '%s'", ErrorUnrecognizedScheme, u.Scheme)
+		if err := storage.Upload(ctx, d, f, dst); err != nil {
+			return nil, err
 		}
 	}
 
@@ -0,0 +1,122 @@
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+const AWSCLIImage = "amazon/aws-cli:2.13.33"
+
+var ErrorInvalidS3URL = errors.New("invalid s3 url")
+
+// ParseS3URL splits an 's3://bucket/key' URL into its bucket and key.
+func ParseS3URL(s3URL string) (string, string, error) {
+	u, err := url.Parse(s3URL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Scheme != "s3" || u.Host == "" {
+		return "", "", fmt.Errorf("%w: '%s'", ErrorInvalidS3URL, s3URL)
+	}
+
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// S3GlobalArgs returns the arguments that are added to every 'aws' command.
+func S3GlobalArgs(opts *S3Opts) []string {
+	args := []string{}
+	if opts.Endpoint != "" {
+		args = append(args, "--endpoint-url", opts.Endpoint)
+	}
+	if opts.Region != "" {
+		args = append(args, "--region", opts.Region)
+	}
+
+	return args
+}
+
+// S3UploadArgs returns the 'aws s3 cp' command that uploads src to the s3 URL dst.
+// Files larger than the multipart threshold are uploaded using multipart uploads, and a SHA256 checksum is sent with every part so
+// that the upload is verified by the server.
+func S3UploadArgs(src, dst string, recursive bool, opts *S3Opts) []string {
+	args := []string{"aws", "s3", "cp"}
+	if recursive {
+		args = append(args, "--recursive")
+	}
+	args = append(args, "--checksum-algorithm", "SHA256", "--no-progress")
+	args = append(args, S3GlobalArgs(opts)...)
+
+	return append(args, src, dst)
+}
+
+// S3DownloadArgs returns the 'aws s3api get-object' command that downloads the object in the s3 URL src to dst.
+// Checksum mode is enabled so that the downloaded object is validated against the checksum that was stored when it was uploaded.
+func S3DownloadArgs(src, dst string, opts *S3Opts) ([]string, error) {
+	bucket, key, err := ParseS3URL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"aws", "s3api", "get-object", "--bucket", bucket, "--key", key, "--checksum-mode", "ENABLED"}
+	args = append(args, S3GlobalArgs(opts)...)
+
+	return append(args, dst), nil
+}
+
+// S3Container returns a container with the aws cli and the credentials in the S3Opts.
+// If no access key is provided, then '$HOME/.aws' is mounted in the container if it exists.
+func S3Container(d *dagger.Client, opts *S3Opts) *dagger.Container {
+	c := d.Container().From(AWSCLIImage).WithEntrypoint([]string{})
+
+	if opts.AccessKeyID != "" {
+		c = c.WithSecretVariable("AWS_ACCESS_KEY_ID", d.SetSecret("aws-access-key-id", opts.AccessKeyID)).
+			WithSecretVariable("AWS_SECRET_ACCESS_KEY", d.SetSecret("aws-secret-access-key", opts.SecretAccessKey))
+		if opts.SessionToken != "" {
+			c = c.WithSecretVariable("AWS_SESSION_TOKEN", d.SetSecret("aws-session-token", opts.SessionToken))
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		if _, err := os.Stat(filepath.Join(home, ".aws")); err == nil {
+			c = c.WithMountedDirectory("/root/.aws", d.Host().Directory(filepath.Join(home, ".aws")))
+		}
+	}
+
+	if opts.PathStyle {
+		c = c.WithExec([]string{"aws", "configure", "set", "default.s3.addressing_style", "path"})
+	}
+
+	return c
+}
+
+func S3UploadFile(d *dagger.Client, opts *S3Opts, file *dagger.File, dst string) *dagger.Container {
+	return S3Container(d, opts).
+		WithMountedFile("/src/file", file).
+		WithExec(S3UploadArgs("/src/file", dst, false, opts))
+}
+
+func S3UploadDirectory(d *dagger.Client, opts *S3Opts, dir *dagger.Directory, dst string) *dagger.Container {
+	return S3Container(d, opts).
+		WithMountedDirectory("/src", dir).
+		WithExec(S3UploadArgs("/src", strings.TrimSuffix(dst, "/")+"/", true, opts))
+}
+
+func S3DownloadFile(d *dagger.Client, opts *S3Opts, src string) (*dagger.File, error) {
+	args, err := S3DownloadArgs(src, "/src/file", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return S3Container(d, opts).
+		WithEnvVariable("RAND", strconv.Itoa(rand.Int())).
+		WithExec([]string{"mkdir", "-p", "/src"}).
+		WithExec(args).
+		File("/src/file"), nil
+}
@@ -0,0 +1,130 @@
+package containers_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/containers"
+)
+
+// s3TestOpts returns the S3Opts for the S3-compatible service in 'GRAFANA_BUILD_TEST_S3_ENDPOINT', like a local MinIO server, and the
+// bucket in 'GRAFANA_BUILD_TEST_S3_BUCKET'. The test is skipped if no endpoint is set.
+// The endpoint must be reachable from inside dagger containers, for example 'http://host.docker.internal:9000'.
+func s3TestOpts(t *testing.T) (*containers.S3Opts, string) {
+	t.Helper()
+	endpoint := os.Getenv("GRAFANA_BUILD_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("GRAFANA_BUILD_TEST_S3_ENDPOINT is not set")
+	}
+
+	bucket := os.Getenv("GRAFANA_BUILD_TEST_S3_BUCKET")
+	if bucket == "" {
+		bucket = "grafana-build-test"
+	}
+
+	return &containers.S3Opts{
+		AccessKeyID:     os.Getenv("GRAFANA_BUILD_TEST_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("GRAFANA_BUILD_TEST_S3_SECRET_ACCESS_KEY"),
+		Region:          "us-east-1",
+		Endpoint:        endpoint,
+		PathStyle:       true,
+	}, bucket
+}
+
+func TestS3StorageIntegration(t *testing.T) {
+	opts, bucket := s3TestOpts(t)
+	ctx := context.Background()
+
+	d, err := dagger.Connect(ctx, dagger.WithLogOutput(os.Stderr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	storage := containers.NewS3Storage(&containers.StorageOpts{S3Opts: opts})
+	prefix := "s3://" + bucket + "/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "/"
+
+	// The file is larger than the default multipart threshold of the aws cli (8MB), so it is uploaded in multiple parts.
+	large := d.Container().From("busybox").
+		WithExec([]string{"/bin/sh", "-c", "head -c 20971520 /dev/urandom > /large"}).
+		File("/large")
+
+	t.Run("upload and download", func(t *testing.T) {
+		dst := prefix + "grafana.tar.gz"
+		if err := storage.Upload(ctx, d, large, dst); err != nil {
+			t.Fatal(err)
+		}
+
+		// Downloading uses '--checksum-mode ENABLED', so this fails if the checksum that was sent with the upload doesn't match.
+		file, err := storage.Download(ctx, d, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect, err := containers.Sha256(d, large).Contents(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := containers.Sha256(d, file).Contents(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Errorf("expected the downloaded file to have checksum '%s' but got '%s'", expect, got)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		_, key, err := containers.ParseS3URL(prefix + "grafana.tar.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Only objects that were uploaded in multiple parts have a second part.
+		args := append([]string{"aws", "s3api", "head-object", "--bucket", bucket, "--key", key, "--part-number", "2", "--checksum-mode", "ENABLED"}, containers.S3GlobalArgs(opts)...)
+		if _, err := containers.ExitError(ctx, containers.S3Container(d, opts).WithExec(args)); err != nil {
+			t.Errorf("expected the object to be uploaded in multiple parts: %s", err)
+		}
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		for path, expect := range map[string]bool{
+			prefix + "grafana.tar.gz": true,
+			prefix + "missing.tar.gz": false,
+		} {
+			exists, err := storage.Exists(ctx, d, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != expect {
+				t.Errorf("expected Exists('%s') to be %t", path, expect)
+			}
+		}
+	})
+
+	t.Run("upload dir and list", func(t *testing.T) {
+		dir := d.Directory().
+			WithNewFile("a.txt", "a").
+			WithNewFile("nested/b.txt", "b")
+
+		if err := storage.UploadDir(ctx, d, dir, prefix+"dir"); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := storage.List(ctx, d, prefix+"dir/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(files)
+
+		expect := []string{prefix + "dir/a.txt", prefix + "dir/nested/b.txt"}
+		if !slices.Equal(files, expect) {
+			t.Errorf("expected %v but got %v", expect, files)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package containers_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/grafana/grafana-build/containers"
+)
+
+func TestParseS3URL(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		bucket, key, err := containers.ParseS3URL("s3://grafana-downloads/artifacts/grafana_v10.0.0_123_linux_amd64.tar.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bucket != "grafana-downloads" {
+			t.Errorf("expected bucket 'grafana-downloads' but got '%s'", bucket)
+		}
+		if key != "artifacts/grafana_v10.0.0_123_linux_amd64.tar.gz" {
+			t.Errorf("expected key 'artifacts/grafana_v10.0.0_123_linux_amd64.tar.gz' but got '%s'", key)
+		}
+	})
+
+	for _, v := range []string{"gs://bucket/key", "s3:///key", "/tmp/file"} {
+		t.Run(v, func(t *testing.T) {
+			if _, _, err := containers.ParseS3URL(v); !errors.Is(err, containers.ErrorInvalidS3URL) {
+				t.Errorf("expected ErrorInvalidS3URL but got %v", err)
+			}
+		})
+	}
+}
+
+func TestS3UploadArgs(t *testing.T) {
+	t.Run("aws", func(t *testing.T) {
+		args := containers.S3UploadArgs("/src/file", "s3://bucket/grafana.tar.gz", false, &containers.S3Opts{})
+		expect := []string{"aws", "s3", "cp", "--checksum-algorithm", "SHA256", "--no-progress", "/src/file", "s3://bucket/grafana.tar.gz"}
+		if !slices.Equal(args, expect) {
+			t.Errorf("expected %v but got %v", expect, args)
+		}
+	})
+
+	t.Run("minio", func(t *testing.T) {
+		args := containers.S3UploadArgs("/src", "s3://bucket/dist/", true, &containers.S3Opts{
+			Endpoint: "http://minio:9000",
+			Region:   "us-east-1",
+		})
+		expect := []string{"aws", "s3", "cp", "--recursive", "--checksum-algorithm", "SHA256", "--no-progress", "--endpoint-url", "http://minio:9000", "--region", "us-east-1", "/src", "s3://bucket/dist/"}
+		if !slices.Equal(args, expect) {
+			t.Errorf("expected %v but got %v", expect, args)
+		}
+	})
+}
+
+func TestS3DownloadArgs(t *testing.T) {
+	args, err := containers.S3DownloadArgs("s3://bucket/dist/grafana.tar.gz", "/src/file", &containers.S3Opts{
+		Endpoint: "http://minio:9000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"aws", "s3api", "get-object", "--bucket", "bucket", "--key", "dist/grafana.tar.gz", "--checksum-mode", "ENABLED", "--endpoint-url", "http://minio:9000", "/src/file"}
+	if !slices.Equal(args, expect) {
+		t.Errorf("expected %v but got %v", expect, args)
+	}
+}
@@ -0,0 +1,110 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"dagger.io/dagger"
+)
+
+// Storage is a location that artifacts can be published to or downloaded from.
+// Every path given to a Storage is the full URL, including the scheme, like 'gs://bucket/grafana.tar.gz' or '/tmp/grafana.tar.gz'.
+type Storage interface {
+	// Upload publishes the file to dst.
+	Upload(ctx context.Context, d *dagger.Client, file *dagger.File, dst string) error
+	// UploadDir publishes the contents of the directory to dst.
+	UploadDir(ctx context.Context, d *dagger.Client, dir *dagger.Directory, dst string) error
+	// Download returns the file at src.
+	Download(ctx context.Context, d *dagger.Client, src string) (*dagger.File, error)
+	// List returns the URLs of every file that starts with the prefix.
+	List(ctx context.Context, d *dagger.Client, prefix string) ([]string, error)
+	// Exists returns true if a file exists at the given path.
+	Exists(ctx context.Context, d *dagger.Client, path string) (bool, error)
+}
+
+// StorageOpts are given to every StorageInitializer. Each implementation uses the options that apply to it.
+type StorageOpts struct {
+	GCPOpts *GCPOpts
+	S3Opts  *S3Opts
+}
+
+// A StorageInitializer creates a Storage for a registered scheme.
+type StorageInitializer func(opts *StorageOpts) Storage
+
+var (
+	storageMutex = &sync.RWMutex{}
+	// storages maps a URL scheme to the Storage that handles it. The empty scheme is used for plain file paths.
+	storages = map[string]StorageInitializer{
+		"":     NewLocalStorage,
+		"file": NewLocalStorage,
+		"fs":   NewLocalStorage,
+		"gs":   NewGCSStorage,
+		"s3":   NewS3Storage,
+	}
+)
+
+// RegisterStorage registers the StorageInitializer for URLs with the given scheme, replacing any Storage that was already registered for it.
+// The returned function restores the Storage that was registered for the scheme before, or removes the scheme if there wasn't one.
+func RegisterStorage(scheme string, fn StorageInitializer) func() {
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	previous, ok := storages[scheme]
+	storages[scheme] = fn
+
+	return func() {
+		storageMutex.Lock()
+		defer storageMutex.Unlock()
+
+		if ok {
+			storages[scheme] = previous
+			return
+		}
+		delete(storages, scheme)
+	}
+}
+
+// StorageForURL returns the registered Storage for the scheme of the URL.
+func StorageForURL(u string, opts *StorageOpts) (Storage, error) {
+	scheme := ""
+	if parsed, err := url.Parse(u); err == nil {
+		scheme = parsed.Scheme
+	}
+
+	storageMutex.RLock()
+	defer storageMutex.RUnlock()
+
+	fn, ok := storages[scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrorUnrecognizedScheme, scheme)
+	}
+
+	if opts == nil {
+		opts = &StorageOpts{}
+	}
+
+	return fn(opts), nil
+}
+
+// NotFoundScript returns a shell script that runs the command and prints its stdout.
+// If the command fails and its stderr contains one of the notFound messages, then the script prints the fallback instead and succeeds.
+// Any other failure, like an authentication or network error, fails the script with the stderr of the command.
+func NotFoundScript(command, fallback string, notFound ...string) string {
+	patterns := make([]string, len(notFound))
+	for i, v := range notFound {
+		patterns[i] = "*'" + v + "'*"
+	}
+
+	return fmt.Sprintf(`stderr=$(mktemp)
+if out=$({ %s; } 2>"$stderr"); then
+  printf '%%s\n' "$out"
+  exit 0
+fi
+case "$(cat "$stderr")" in
+  %s) printf '%%s\n' '%s' ;;
+  *) cat "$stderr" >&2; exit 1 ;;
+esac`, command, strings.Join(patterns, "|"), fallback)
+}
@@ -0,0 +1,105 @@
+package containers
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// GCSStorage is a Storage for 'gs://' URLs that uses the Google Cloud SDK.
+type GCSStorage struct {
+	Opts *GCPOpts
+}
+
+func NewGCSStorage(opts *StorageOpts) Storage {
+	gcpOpts := opts.GCPOpts
+	if gcpOpts == nil {
+		gcpOpts = &GCPOpts{}
+	}
+
+	return &GCSStorage{
+		Opts: gcpOpts,
+	}
+}
+
+func (s *GCSStorage) Upload(ctx context.Context, d *dagger.Client, file *dagger.File, dst string) error {
+	uploader, err := GCSUploadFile(d, GoogleCloudImage, GCSAuth(d, s.Opts), file, dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ExitError(ctx, uploader); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *GCSStorage) UploadDir(ctx context.Context, d *dagger.Client, dir *dagger.Directory, dst string) error {
+	uploader, err := GCSUploadDirectory(d, GoogleCloudImage, GCSAuth(d, s.Opts), dir, dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ExitError(ctx, uploader); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *GCSStorage) Download(ctx context.Context, d *dagger.Client, src string) (*dagger.File, error) {
+	return GCSDownloadFile(d, GoogleCloudImage, GCSAuth(d, s.Opts), src)
+}
+
+// gcsNotFound is printed by gcloud when a URL doesn't match any objects.
+const gcsNotFound = "matched no objects"
+
+// gcloud runs the shell script with the URL in the $GCS_URL environment variable and returns its stdout.
+func (s *GCSStorage) gcloud(ctx context.Context, d *dagger.Client, u, script string) (string, error) {
+	container, err := GCSAuth(d, s.Opts).Authenticate(d, d.Container().From(GoogleCloudImage))
+	if err != nil {
+		return "", err
+	}
+
+	// Listing should never be cached
+	container, err = ExitError(ctx, container.
+		WithEnvVariable("RAND", strconv.Itoa(rand.Int())).
+		WithSecretVariable("GCS_URL", d.SetSecret("gcs-url", u)).
+		WithExec([]string{"/bin/sh", "-c", script}))
+	if err != nil {
+		return "", err
+	}
+
+	return container.Stdout(ctx)
+}
+
+func (s *GCSStorage) List(ctx context.Context, d *dagger.Client, prefix string) ([]string, error) {
+	out, err := s.gcloud(ctx, d, prefix, NotFoundScript(`gcloud storage ls -r "${GCS_URL}*"`, "", gcsNotFound))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, v := range strings.Split(out, "\n") {
+		// Folders are listed with a trailing '/' or ':'
+		if v == "" || strings.HasSuffix(v, "/") || strings.HasSuffix(v, ":") {
+			continue
+		}
+		files = append(files, v)
+	}
+
+	return files, nil
+}
+
+func (s *GCSStorage) Exists(ctx context.Context, d *dagger.Client, path string) (bool, error) {
+	out, err := s.gcloud(ctx, d, path, NotFoundScript(`gcloud storage objects describe "${GCS_URL}" > /dev/null && echo true`, "false", gcsNotFound))
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(out) == "true", nil
+}
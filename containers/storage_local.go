@@ -0,0 +1,89 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// LocalStorage is a Storage on the local filesystem. Paths can be plain file paths or 'file://' / 'fs://' URLs.
+type LocalStorage struct{}
+
+func NewLocalStorage(opts *StorageOpts) Storage {
+	return &LocalStorage{}
+}
+
+func localPath(p string) string {
+	u, err := url.Parse(p)
+	if err != nil || u.Scheme == "" {
+		return p
+	}
+
+	return strings.TrimPrefix(u.String(), u.Scheme+"://")
+}
+
+func (s *LocalStorage) Upload(ctx context.Context, d *dagger.Client, file *dagger.File, dst string) error {
+	if _, err := file.Export(ctx, localPath(dst)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) UploadDir(ctx context.Context, d *dagger.Client, dir *dagger.Directory, dst string) error {
+	if _, err := dir.Export(ctx, localPath(dst)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Download(ctx context.Context, d *dagger.Client, src string) (*dagger.File, error) {
+	p := localPath(src)
+	// pending https://github.com/dagger/dagger/issues/4745
+	return d.Host().Directory(filepath.Dir(p)).File(filepath.Base(p)), nil
+}
+
+func (s *LocalStorage) List(ctx context.Context, d *dagger.Client, prefix string) ([]string, error) {
+	p := localPath(prefix)
+	root := p
+	if info, err := os.Stat(p); err != nil || !info.IsDir() {
+		root = filepath.Dir(p)
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() || !strings.HasPrefix(path, p) {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+
+	return files, err
+}
+
+func (s *LocalStorage) Exists(ctx context.Context, d *dagger.Client, path string) (bool, error) {
+	_, err := os.Stat(localPath(path))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
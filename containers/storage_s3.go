@@ -0,0 +1,102 @@
+package containers
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// S3Storage is a Storage for 's3://' URLs that uses the AWS CLI.
+type S3Storage struct {
+	Opts *S3Opts
+}
+
+func NewS3Storage(opts *StorageOpts) Storage {
+	s3Opts := opts.S3Opts
+	if s3Opts == nil {
+		s3Opts = &S3Opts{}
+	}
+
+	return &S3Storage{
+		Opts: s3Opts,
+	}
+}
+
+func (s *S3Storage) Upload(ctx context.Context, d *dagger.Client, file *dagger.File, dst string) error {
+	if _, err := ExitError(ctx, S3UploadFile(d, s.Opts, file, dst)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *S3Storage) UploadDir(ctx context.Context, d *dagger.Client, dir *dagger.Directory, dst string) error {
+	if _, err := ExitError(ctx, S3UploadDirectory(d, s.Opts, dir, dst)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Download(ctx context.Context, d *dagger.Client, src string) (*dagger.File, error) {
+	return S3DownloadFile(d, s.Opts, src)
+}
+
+// s3NotFound is printed by the aws cli when 'head-object' is used on a key that doesn't exist.
+const s3NotFound = "(404)"
+
+// aws runs the shell script with the arguments to the aws command in "$@" and returns its stdout.
+func (s *S3Storage) aws(ctx context.Context, d *dagger.Client, script string, args []string) (string, error) {
+	// Listing should never be cached
+	container, err := ExitError(ctx, S3Container(d, s.Opts).
+		WithEnvVariable("RAND", strconv.Itoa(rand.Int())).
+		WithExec(append([]string{"/bin/sh", "-c", script, "aws"}, args...)))
+	if err != nil {
+		return "", err
+	}
+
+	return container.Stdout(ctx)
+}
+
+func (s *S3Storage) List(ctx context.Context, d *dagger.Client, prefix string) ([]string, error) {
+	bucket, key, err := ParseS3URL(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"s3api", "list-objects-v2", "--bucket", bucket, "--prefix", key, "--query", "Contents[].[Key]", "--output", "text"}, S3GlobalArgs(s.Opts)...)
+	out, err := s.aws(ctx, d, `aws "$@"`, args)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	// Selecting '[Key]' prints every key on its own line, so keys can contain spaces.
+	for _, v := range strings.Split(out, "\n") {
+		// The aws cli prints 'None' when there are no results
+		if v == "" || v == "None" {
+			continue
+		}
+		files = append(files, "s3://"+bucket+"/"+v)
+	}
+
+	return files, nil
+}
+
+func (s *S3Storage) Exists(ctx context.Context, d *dagger.Client, path string) (bool, error) {
+	bucket, key, err := ParseS3URL(path)
+	if err != nil {
+		return false, err
+	}
+
+	args := append([]string{"s3api", "head-object", "--bucket", bucket, "--key", key}, S3GlobalArgs(s.Opts)...)
+	out, err := s.aws(ctx, d, NotFoundScript(`aws "$@" > /dev/null && echo true`, "false", s3NotFound), args)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(out) == "true", nil
+}
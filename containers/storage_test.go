@@ -0,0 +1,218 @@
+package containers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/containers"
+)
+
+// memStorage is an in-memory containers.Storage that records which paths were uploaded.
+type memStorage struct {
+	mutex *sync.Mutex
+	files map[string]*dagger.File
+}
+
+func (s *memStorage) Upload(ctx context.Context, d *dagger.Client, file *dagger.File, dst string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.files[dst] = file
+	return nil
+}
+
+func (s *memStorage) UploadDir(ctx context.Context, d *dagger.Client, dir *dagger.Directory, dst string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.files[strings.TrimSuffix(dst, "/")+"/"] = nil
+	return nil
+}
+
+func (s *memStorage) Download(ctx context.Context, d *dagger.Client, src string) (*dagger.File, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	f, ok := s.files[src]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return f, nil
+}
+
+func (s *memStorage) List(ctx context.Context, d *dagger.Client, prefix string) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	files := []string{}
+	for k := range s.files {
+		if strings.HasPrefix(k, prefix) {
+			files = append(files, k)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (s *memStorage) Exists(ctx context.Context, d *dagger.Client, path string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.files[path]
+	return ok, nil
+}
+
+// newMemStorage registers a memStorage for the scheme until the test finishes.
+func newMemStorage(t *testing.T, scheme string) *memStorage {
+	t.Helper()
+	s := &memStorage{
+		mutex: &sync.Mutex{},
+		files: map[string]*dagger.File{},
+	}
+	t.Cleanup(containers.RegisterStorage(scheme, func(*containers.StorageOpts) containers.Storage {
+		return s
+	}))
+
+	return s
+}
+
+func TestStorageForURL(t *testing.T) {
+	cases := map[string]string{
+		"/tmp/grafana.tar.gz":        "*containers.LocalStorage",
+		"dist":                       "*containers.LocalStorage",
+		"file:///tmp/grafana.tar.gz": "*containers.LocalStorage",
+		"fs://dist/grafana.tar.gz":   "*containers.LocalStorage",
+		"gs://bucket/grafana.tar.gz": "*containers.GCSStorage",
+		"s3://bucket/grafana.tar.gz": "*containers.S3Storage",
+	}
+
+	for k, v := range cases {
+		s, err := containers.StorageForURL(k, nil)
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %s", k, err)
+		}
+
+		if n := fmt.Sprintf("%T", s); n != v {
+			t.Errorf("expected '%s' to use %s but got %s", k, v, n)
+		}
+	}
+
+	if _, err := containers.StorageForURL("ftp://example.com/grafana.tar.gz", nil); !errors.Is(err, containers.ErrorUnrecognizedScheme) {
+		t.Errorf("expected ErrorUnrecognizedScheme but got %v", err)
+	}
+}
+
+func TestPublishWithStorage(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		storage = newMemStorage(t, "mem")
+	)
+
+	out, err := containers.PublishFile(ctx, nil, &containers.PublishFileOpts{
+		PublishOpts: &containers.PublishOpts{},
+		Destination: "mem://bucket/grafana.tar.gz",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"mem://bucket/grafana.tar.gz"}; !slices.Equal(out, expect) {
+		t.Errorf("expected PublishFile to return %v but got %v", expect, out)
+	}
+
+	dst, err := containers.PublishDirectory(ctx, nil, nil, nil, nil, "mem://bucket/dist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dst != "mem://bucket/dist" {
+		t.Errorf("expected PublishDirectory to return 'mem://bucket/dist' but got '%s'", dst)
+	}
+
+	files, err := storage.List(ctx, nil, "mem://bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"mem://bucket/dist/", "mem://bucket/grafana.tar.gz"}; !slices.Equal(files, expect) {
+		t.Errorf("expected storage to contain %v but got %v", expect, files)
+	}
+
+	if _, err := containers.GetPackages(ctx, nil, &containers.PackageInputOpts{
+		Packages: []string{"mem://bucket/grafana.tar.gz"},
+	}, nil, nil); err != nil {
+		t.Errorf("expected uploaded package to be downloaded but got %s", err)
+	}
+
+	if _, err := containers.GetPackages(ctx, nil, &containers.PackageInputOpts{
+		Packages: []string{"mem://bucket/missing.tar.gz"},
+	}, nil, nil); err == nil {
+		t.Errorf("expected an error when getting a package that doesn't exist")
+	}
+}
+
+func TestRegisterStorageRestore(t *testing.T) {
+	restore := containers.RegisterStorage("gs", func(*containers.StorageOpts) containers.Storage {
+		return &containers.LocalStorage{}
+	})
+	restoreMem := containers.RegisterStorage("mem", func(*containers.StorageOpts) containers.Storage {
+		return &containers.LocalStorage{}
+	})
+
+	restore()
+	restoreMem()
+
+	if s, err := containers.StorageForURL("gs://bucket/grafana.tar.gz", nil); err != nil {
+		t.Fatal(err)
+	} else if _, ok := s.(*containers.GCSStorage); !ok {
+		t.Errorf("expected 'gs' to be restored to *containers.GCSStorage but got %T", s)
+	}
+
+	if _, err := containers.StorageForURL("mem://bucket/grafana.tar.gz", nil); !errors.Is(err, containers.ErrorUnrecognizedScheme) {
+		t.Errorf("expected 'mem' to be removed but got %v", err)
+	}
+}
+
+func TestNotFoundScript(t *testing.T) {
+	cases := map[string]struct {
+		command string
+		stdout  string
+		fail    bool
+	}{
+		"found": {
+			command: "echo true",
+			stdout:  "true\n",
+		},
+		"not found": {
+			command: "echo 'An error occurred (404) when calling the HeadObject operation: Not Found' >&2; exit 254",
+			stdout:  "false\n",
+		},
+		"access denied": {
+			command: "echo 'An error occurred (403) when calling the HeadObject operation: Forbidden' >&2; exit 254",
+			fail:    true,
+		},
+		"network error": {
+			command: "echo 'Could not connect to the endpoint URL' >&2; exit 255",
+			fail:    true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command("/bin/sh", "-c", containers.NotFoundScript(c.command, "false", "(404)"))
+			out, err := cmd.Output()
+			if c.fail {
+				if err == nil {
+					t.Errorf("expected the script to fail but it printed '%s'", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != c.stdout {
+				t.Errorf("expected stdout '%s' but got '%s'", c.stdout, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// decompress returns a reader of the decompressed stream if r is gzipped, or r itself if it is not.
+// 'docker save' writes plain tar files even if they are named '.tar.gz', so the compression can't be known from the name.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return br, nil
+	}
+
+	return gzip.NewReader(br)
+}
+
+// walkTar calls fn with the header and the contents of every entry in the (optionally gzipped) tar archive.
+func walkTar(r io.Reader, fn func(*tar.Header, io.Reader) error) error {
+	r, err := decompress(r)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(h, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadTarGZ reads the files in a tar.gz package. The top-level directory, like 'grafana-10.2.0/', is removed from the paths.
+func ReadTarGZ(r io.Reader) (*Contents, error) {
+	c := newContents(TypeTarGZ)
+	if err := walkTar(r, c.addTarEntry); err != nil {
+		return nil, err
+	}
+
+	c.stripTopLevel()
+	return c, nil
+}
+
+// addTarEntry adds the tar entry to the contents.
+func (c *Contents) addTarEntry(h *tar.Header, r io.Reader) error {
+	f, err := readFile(h.Name, h.FileInfo().Mode(), h.Linkname, r)
+	if err != nil {
+		return err
+	}
+
+	c.add(f)
+	return nil
+}
+
+// ReadZip reads the files in a zip package. The top-level directory, like 'grafana-10.2.0/', is removed from the paths.
+func ReadZip(r io.Reader) (*Contents, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	c := newContents(TypeZip)
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error opening '%s': %w", zf.Name, err)
+		}
+		f, err := readFile(zf.Name, zf.Mode(), "", rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.add(f)
+	}
+
+	c.stripTopLevel()
+	return c, nil
+}
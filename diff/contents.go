@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrorUnknownType is returned when the type of an artifact can't be determined from its file name.
+var ErrorUnknownType = errors.New("unknown artifact type")
+
+// Type is the kind of artifact, which decides how its files and metadata are read.
+type Type string
+
+const (
+	TypeTarGZ  Type = "tar.gz"
+	TypeZip    Type = "zip"
+	TypeDeb    Type = "deb"
+	TypeRPM    Type = "rpm"
+	TypeDocker Type = "docker"
+)
+
+// TypeFromName returns the type of the artifact from its file name, like 'grafana_10.2.0_amd64.deb'.
+// Docker images are the archives created with 'docker save', like 'grafana_10.2.0_linux_amd64.docker.tar.gz'.
+func TypeFromName(name string) (Type, error) {
+	name = path.Base(name)
+	switch {
+	case strings.HasSuffix(name, ".docker.tar.gz"):
+		return TypeDocker, nil
+	case strings.HasSuffix(name, ".tar.gz"):
+		return TypeTarGZ, nil
+	case strings.HasSuffix(name, ".zip"):
+		return TypeZip, nil
+	case strings.HasSuffix(name, ".deb"):
+		return TypeDeb, nil
+	case strings.HasSuffix(name, ".rpm"):
+		return TypeRPM, nil
+	}
+
+	return "", fmt.Errorf("%w: '%s'", ErrorUnknownType, name)
+}
+
+// A File is a single file, directory, or link in an artifact.
+type File struct {
+	Path string
+	Size int64
+	Mode fs.FileMode
+	// Sha256 is the checksum of the contents of regular files.
+	Sha256 string
+	// Link is the target of a symbolic link.
+	Link string
+}
+
+// Contents are the files and the package metadata of an artifact.
+type Contents struct {
+	Type Type
+	// Size is the size of the artifact itself.
+	Size  int64
+	Files map[string]File
+	// Metadata has the deb control fields, rpm headers, or image config, depending on the type.
+	Metadata map[string]string
+}
+
+func newContents(t Type) *Contents {
+	return &Contents{
+		Type:     t,
+		Files:    map[string]File{},
+		Metadata: map[string]string{},
+	}
+}
+
+// add adds the file to the contents. Paths are cleaned so that './bin' and 'bin/' are the same file.
+func (c *Contents) add(f File) {
+	f.Path = cleanPath(f.Path)
+	if f.Path == "" {
+		return
+	}
+	c.Files[f.Path] = f
+}
+
+func cleanPath(p string) string {
+	p = path.Clean("/" + p)
+	return strings.TrimPrefix(p, "/")
+}
+
+// stripTopLevel removes the directory that every file is in, like 'grafana-10.2.0/', so that the files of two versions can be compared.
+func (c *Contents) stripTopLevel() {
+	var top string
+	for p := range c.Files {
+		dir, _, _ := strings.Cut(p, "/")
+		if top == "" {
+			top = dir
+		}
+		if dir != top {
+			return
+		}
+	}
+
+	if f, ok := c.Files[top]; top == "" || (ok && !f.Mode.IsDir()) {
+		return
+	}
+
+	files := make(map[string]File, len(c.Files))
+	for p, f := range c.Files {
+		p = strings.TrimPrefix(strings.TrimPrefix(p, top), "/")
+		if p == "" {
+			continue
+		}
+		f.Path = p
+		files[p] = f
+	}
+	c.Files = files
+}
+
+// readFile reads a file in an archive, computing its checksum.
+func readFile(name string, mode fs.FileMode, link string, r io.Reader) (File, error) {
+	f := File{
+		Path: name,
+		Mode: mode,
+		Link: link,
+	}
+	if !mode.IsRegular() {
+		return f, nil
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return f, fmt.Errorf("error reading '%s': %w", name, err)
+	}
+	f.Size = n
+	f.Sha256 = hex.EncodeToString(h.Sum(nil))
+
+	return f, nil
+}
+
+// unixMode converts the mode bits of a cpio or ar header to an fs.FileMode.
+func unixMode(m uint32) fs.FileMode {
+	mode := fs.FileMode(m & 0o777)
+	switch m & 0o170000 {
+	case 0o040000:
+		mode |= fs.ModeDir
+	case 0o120000:
+		mode |= fs.ModeSymlink
+	case 0o020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		mode |= fs.ModeDevice
+	case 0o010000:
+		mode |= fs.ModeNamedPipe
+	case 0o140000:
+		mode |= fs.ModeSocket
+	}
+	if m&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+
+	return mode
+}
+
+// ReadFile reads the contents of the artifact at path. The type of the artifact is determined by the file name.
+func ReadFile(name string) (*Contents, error) {
+	t, err := TypeFromName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Read(f, t)
+	if err != nil {
+		return nil, fmt.Errorf("error reading '%s': %w", name, err)
+	}
+	c.Size = info.Size()
+
+	return c, nil
+}
+
+// Read reads the contents of an artifact of type t.
+func Read(r io.Reader, t Type) (*Contents, error) {
+	switch t {
+	case TypeTarGZ:
+		return ReadTarGZ(r)
+	case TypeZip:
+		return ReadZip(r)
+	case TypeDeb:
+		return ReadDeb(r)
+	case TypeRPM:
+		return ReadRPM(r)
+	case TypeDocker:
+		return ReadDocker(r)
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrorUnknownType, t)
+}
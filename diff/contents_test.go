@@ -0,0 +1,360 @@
+package diff_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/grafana/grafana-build/diff"
+)
+
+type testFile struct {
+	name string
+	mode int64
+	body string
+	link string
+}
+
+func writeTar(t *testing.T, files []testFile, compress bool) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	var (
+		gz *gzip.Writer
+		tw *tar.Writer
+	)
+	if compress {
+		gz = gzip.NewWriter(buf)
+		tw = tar.NewWriter(gz)
+	} else {
+		tw = tar.NewWriter(buf)
+	}
+
+	for _, f := range files {
+		h := &tar.Header{Name: f.name, Mode: f.mode & 0o7777, Size: int64(len(f.body)), Typeflag: tar.TypeReg}
+		switch {
+		case f.link != "":
+			h.Typeflag, h.Linkname, h.Size = tar.TypeSymlink, f.link, 0
+		case f.mode&0o040000 != 0:
+			h.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(f.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func writeAr(members map[string][]byte, order ...string) []byte {
+	buf := bytes.NewBufferString("!<arch>\n")
+	for _, name := range order {
+		b := members[name]
+		fmt.Fprintf(buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", name, 0, 0, 0, "100644", len(b))
+		buf.Write(b)
+		if len(b)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeCPIO(t *testing.T, files []testFile) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	pad := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	write := func(name string, mode int64, body string) {
+		fmt.Fprintf(buf, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X", 0, mode, 0, 0, 1, 0, len(body), 0, 0, 0, 0, len(name)+1, 0)
+		buf.WriteString(name + "\x00")
+		pad()
+		buf.WriteString(body)
+		pad()
+	}
+	for _, f := range files {
+		mode, body := f.mode, f.body
+		if f.link != "" {
+			mode, body = 0o120777, f.link
+		} else if mode&0o040000 == 0 {
+			mode |= 0o100000
+		}
+		write(f.name, mode, body)
+	}
+	write("TRAILER!!!", 0, "")
+
+	gzbuf := &bytes.Buffer{}
+	gz := gzip.NewWriter(gzbuf)
+	if _, err := gz.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return gzbuf.Bytes()
+}
+
+type rpmTag struct {
+	tag, typ uint32
+	values   []string
+}
+
+func writeRPMHeader(tags []rpmTag) []byte {
+	var (
+		index = &bytes.Buffer{}
+		store = &bytes.Buffer{}
+	)
+	for _, tag := range tags {
+		binary.Write(index, binary.BigEndian, []uint32{tag.tag, tag.typ, uint32(store.Len()), uint32(len(tag.values))})
+		for _, v := range tag.values {
+			store.WriteString(v + "\x00")
+		}
+	}
+
+	buf := bytes.NewBuffer([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+	binary.Write(buf, binary.BigEndian, []uint32{uint32(len(tags)), uint32(store.Len())})
+	buf.Write(index.Bytes())
+	buf.Write(store.Bytes())
+	return buf.Bytes()
+}
+
+func writeRPM(t *testing.T, tags []rpmTag, files []testFile) []byte {
+	buf := bytes.NewBuffer(make([]byte, 96))
+	buf.Write(writeRPMHeader(nil))
+	buf.Write(writeRPMHeader(tags))
+	buf.Write(writeCPIO(t, files))
+	return buf.Bytes()
+}
+
+func writeZip(t *testing.T, files []testFile) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, f := range files {
+		h := &zip.FileHeader{Name: f.name}
+		h.SetMode(fs.FileMode(f.mode & 0o777))
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeDocker(t *testing.T, config string, layers ...[]testFile) []byte {
+	t.Helper()
+	var (
+		files    = []testFile{{name: "config.json", mode: 0o644, body: config}}
+		manifest = []map[string]any{{"Config": "config.json", "RepoTags": []string{"grafana/grafana:test"}}}
+		paths    []string
+	)
+	for i, l := range layers {
+		p := fmt.Sprintf("layer%d/layer.tar", i)
+		paths = append(paths, p)
+		files = append(files, testFile{name: p, mode: 0o644, body: string(writeTar(t, l, false))})
+	}
+	manifest[0]["Layers"] = paths
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, testFile{name: "manifest.json", mode: 0o644, body: string(b)})
+
+	return writeTar(t, files, false)
+}
+
+func TestTypeFromName(t *testing.T) {
+	cases := map[string]diff.Type{
+		"grafana_10.2.0_abc_linux_amd64.tar.gz":               diff.TypeTarGZ,
+		"gs://bucket/grafana_10.2.0_abc_linux_amd64.tar.gz":   diff.TypeTarGZ,
+		"grafana_10.2.0_abc_linux_amd64.docker.tar.gz":        diff.TypeDocker,
+		"grafana_10.2.0_abc_linux_amd64.ubuntu.docker.tar.gz": diff.TypeDocker,
+		"grafana_10.2.0_abc_windows_amd64.zip":                diff.TypeZip,
+		"grafana_10.2.0_abc_amd64.deb":                        diff.TypeDeb,
+		"grafana_10.2.0_abc_x86_64.rpm":                       diff.TypeRPM,
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			typ, err := diff.TypeFromName(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ != v {
+				t.Errorf("expected %s but got %s", v, typ)
+			}
+		})
+	}
+
+	if _, err := diff.TypeFromName("grafana.exe"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
+
+func TestRead(t *testing.T) {
+	var (
+		control = writeTar(t, []testFile{
+			{name: "./control", mode: 0o644, body: "Package: grafana\nVersion: 10.2.0\nDescription: Grafana\n with a second line\n"},
+			{name: "./postinst", mode: 0o755, body: "#!/bin/sh\n"},
+		}, true)
+		data = writeTar(t, []testFile{
+			{name: "./usr/", mode: 0o040755},
+			{name: "./usr/sbin/grafana", mode: 0o755, body: "binary"},
+		}, true)
+	)
+
+	cases := map[string]struct {
+		typ      diff.Type
+		artifact []byte
+		files    map[string]fs.FileMode
+		metadata map[string]string
+	}{
+		"tar.gz without its top-level directory": {
+			typ: diff.TypeTarGZ,
+			artifact: writeTar(t, []testFile{
+				{name: "grafana-10.2.0/", mode: 0o040755},
+				{name: "grafana-10.2.0/bin/grafana", mode: 0o755, body: "binary"},
+				{name: "grafana-10.2.0/bin/grafana-server", mode: 0o777, link: "grafana"},
+			}, true),
+			files: map[string]fs.FileMode{
+				"bin/grafana":        0o755,
+				"bin/grafana-server": fs.ModeSymlink | 0o777,
+			},
+		},
+		"zip": {
+			typ: diff.TypeZip,
+			artifact: writeZip(t, []testFile{
+				{name: "grafana-10.2.0/bin/grafana.exe", mode: 0o644, body: "binary"},
+				{name: "grafana-10.2.0/conf/defaults.ini", mode: 0o644, body: "[paths]"},
+			}),
+			files: map[string]fs.FileMode{
+				"bin/grafana.exe":   0o644,
+				"conf/defaults.ini": 0o644,
+			},
+		},
+		"deb": {
+			typ: diff.TypeDeb,
+			artifact: writeAr(map[string][]byte{
+				"debian-binary":  []byte("2.0\n"),
+				"control.tar.gz": control,
+				"data.tar.gz":    data,
+			}, "debian-binary", "control.tar.gz", "data.tar.gz"),
+			files: map[string]fs.FileMode{
+				"DEBIAN/postinst":  0o755,
+				"usr":              fs.ModeDir | 0o755,
+				"usr/sbin/grafana": 0o755,
+			},
+			metadata: map[string]string{
+				"Package":     "grafana",
+				"Version":     "10.2.0",
+				"Description": "Grafana\nwith a second line",
+			},
+		},
+		"rpm": {
+			typ: diff.TypeRPM,
+			artifact: writeRPM(t, []rpmTag{
+				{tag: 1000, typ: 6, values: []string{"grafana"}},
+				{tag: 1001, typ: 6, values: []string{"10.2.0"}},
+				{tag: 1024, typ: 6, values: []string{"systemctl daemon-reload"}},
+				{tag: 1049, typ: 8, values: []string{"/bin/sh", "fontconfig"}},
+			}, []testFile{
+				{name: "./etc/grafana", mode: 0o040755},
+				{name: "./usr/sbin/grafana", mode: 0o755, body: "binary"},
+				{name: "./usr/bin/grafana", link: "../sbin/grafana"},
+			}),
+			files: map[string]fs.FileMode{
+				"SCRIPTS/postin":   0o755,
+				"etc/grafana":      fs.ModeDir | 0o755,
+				"usr/sbin/grafana": 0o755,
+				"usr/bin/grafana":  fs.ModeSymlink | 0o777,
+			},
+			metadata: map[string]string{
+				"Name":     "grafana",
+				"Version":  "10.2.0",
+				"Requires": "/bin/sh\nfontconfig",
+			},
+		},
+		"docker with whiteouts": {
+			typ: diff.TypeDocker,
+			artifact: writeDocker(t,
+				`{"architecture":"amd64","os":"linux","config":{"User":"472","Env":["GF_PATHS_HOME=/usr/share/grafana"],"Labels":{"maintainer":"Grafana Labs"}}}`,
+				[]testFile{
+					{name: "etc/", mode: 0o040755},
+					{name: "etc/motd", mode: 0o644, body: "hello"},
+					{name: "tmp/", mode: 0o041777},
+					{name: "tmp/cache", mode: 0o644, body: "cache"},
+				},
+				[]testFile{
+					{name: "etc/.wh.motd", mode: 0o644},
+					{name: "tmp/.wh..wh..opq", mode: 0o644},
+					{name: "usr/share/grafana/bin/grafana", mode: 0o755, body: "binary"},
+				},
+			),
+			files: map[string]fs.FileMode{
+				"etc":                           fs.ModeDir | 0o755,
+				"tmp":                           fs.ModeDir | fs.ModeSticky | 0o777,
+				"usr/share/grafana/bin/grafana": 0o755,
+			},
+			metadata: map[string]string{
+				"Architecture":      "amd64",
+				"OS":                "linux",
+				"User":              "472",
+				"Env.GF_PATHS_HOME": "/usr/share/grafana",
+				"Labels.maintainer": "Grafana Labs",
+			},
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			c, err := diff.Read(bytes.NewReader(v.artifact), v.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.Files) != len(v.files) {
+				t.Errorf("expected %d files but got %d: %v", len(v.files), len(c.Files), c.Files)
+			}
+			for name, mode := range v.files {
+				f, ok := c.Files[name]
+				if !ok {
+					t.Errorf("expected file '%s'", name)
+					continue
+				}
+				if f.Mode != mode {
+					t.Errorf("expected mode of '%s' to be %s but got %s", name, mode, f.Mode)
+				}
+			}
+
+			for key, value := range v.metadata {
+				if c.Metadata[key] != value {
+					t.Errorf("expected metadata '%s' to be %q but got %q", key, value, c.Metadata[key])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const arMagic = "!<arch>\n"
+
+// walkAr calls fn with the name and the contents of every member of the ar archive.
+func walkAr(r io.Reader, fn func(name string, r io.Reader) error) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != arMagic {
+		return errors.New("not an ar archive")
+	}
+
+	header := make([]byte, 60)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid size of ar member '%s': %w", name, err)
+		}
+
+		lr := io.LimitReader(br, size)
+		if err := fn(name, lr); err != nil {
+			return err
+		}
+		// Skip whatever fn didn't read, and the padding to an even offset.
+		if _, err := io.Copy(io.Discard, lr); err != nil {
+			return err
+		}
+		if size%2 == 1 {
+			if _, err := br.Discard(1); err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+		}
+	}
+}
+
+// parseControl parses the fields of a deb control file. Continuation lines are joined with a newline.
+func parseControl(r io.Reader, fields map[string]string) error {
+	var key string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if key != "" && (line[0] == ' ' || line[0] == '\t') {
+			fields[key] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("invalid line in control file: '%s'", line)
+		}
+		key = strings.TrimSpace(k)
+		fields[key] = strings.TrimSpace(v)
+	}
+
+	return s.Err()
+}
+
+// ReadDeb reads the files and the control fields of a deb package.
+// The maintainer scripts, like 'postinst', are added as files in 'DEBIAN/'.
+// Only gzipped or uncompressed control and data archives are supported.
+func ReadDeb(r io.Reader) (*Contents, error) {
+	c := newContents(TypeDeb)
+	err := walkAr(r, func(name string, r io.Reader) error {
+		switch {
+		case strings.HasPrefix(name, "control.tar"):
+			if ext := path.Ext(name); ext != ".tar" && ext != ".gz" {
+				return fmt.Errorf("unsupported compression of '%s'", name)
+			}
+			return walkTar(r, func(h *tar.Header, r io.Reader) error {
+				if h.Typeflag != tar.TypeReg {
+					return nil
+				}
+				if cleanPath(h.Name) == "control" {
+					return parseControl(r, c.Metadata)
+				}
+				f, err := readFile(path.Join("DEBIAN", h.Name), h.FileInfo().Mode(), "", r)
+				if err != nil {
+					return err
+				}
+				c.add(f)
+				return nil
+			})
+		case strings.HasPrefix(name, "data.tar"):
+			if ext := path.Ext(name); ext != ".tar" && ext != ".gz" {
+				return fmt.Errorf("unsupported compression of '%s'", name)
+			}
+			return walkTar(r, c.addTarEntry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
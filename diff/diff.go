@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"sort"
+)
+
+// ChangeType is the kind of change to a file or a metadata field.
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// A FileChange is a file that was added, removed, or changed between the two artifacts.
+type FileChange struct {
+	Path string     `json:"path"`
+	Type ChangeType `json:"type"`
+
+	OldSize   int64 `json:"oldSize"`
+	NewSize   int64 `json:"newSize"`
+	SizeDelta int64 `json:"sizeDelta"`
+
+	OldMode string `json:"oldMode,omitempty"`
+	NewMode string `json:"newMode,omitempty"`
+	// ModeChanged is true if the permissions or the type of the file changed.
+	ModeChanged bool `json:"modeChanged,omitempty"`
+	// ContentChanged is true if the checksum or the link target of the file changed.
+	ContentChanged bool `json:"contentChanged,omitempty"`
+}
+
+// A MetadataChange is a package metadata field, like a deb control field or an image label, that was added, removed, or changed.
+type MetadataChange struct {
+	Key  string     `json:"key"`
+	Type ChangeType `json:"type"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// Result is the difference between two artifacts.
+type Result struct {
+	Old  string `json:"old"`
+	New  string `json:"new"`
+	Type Type   `json:"type"`
+
+	// OldSize and NewSize are the sizes of the artifacts themselves.
+	OldSize   int64 `json:"oldSize"`
+	NewSize   int64 `json:"newSize"`
+	SizeDelta int64 `json:"sizeDelta"`
+
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+
+	Files    []FileChange     `json:"files"`
+	Metadata []MetadataChange `json:"metadata"`
+}
+
+// Empty returns true if the artifacts have the same files and metadata.
+func (r *Result) Empty() bool {
+	return len(r.Files) == 0 && len(r.Metadata) == 0
+}
+
+// Compare returns the files and the metadata that differ between two artifacts. Changes are sorted by path and by key.
+func Compare(oldName string, before *Contents, newName string, after *Contents) *Result {
+	r := &Result{
+		Old:       oldName,
+		New:       newName,
+		Type:      after.Type,
+		OldSize:   before.Size,
+		NewSize:   after.Size,
+		SizeDelta: after.Size - before.Size,
+		Files:     []FileChange{},
+		Metadata:  []MetadataChange{},
+	}
+
+	for p, o := range before.Files {
+		n, ok := after.Files[p]
+		if !ok {
+			r.Files = append(r.Files, FileChange{
+				Path:      p,
+				Type:      Removed,
+				OldSize:   o.Size,
+				SizeDelta: -o.Size,
+				OldMode:   o.Mode.String(),
+			})
+			r.Removed++
+			continue
+		}
+
+		var (
+			modeChanged    = o.Mode != n.Mode
+			contentChanged = o.Sha256 != n.Sha256 || o.Link != n.Link
+		)
+		if !modeChanged && !contentChanged {
+			continue
+		}
+		r.Files = append(r.Files, FileChange{
+			Path:           p,
+			Type:           Changed,
+			OldSize:        o.Size,
+			NewSize:        n.Size,
+			SizeDelta:      n.Size - o.Size,
+			OldMode:        o.Mode.String(),
+			NewMode:        n.Mode.String(),
+			ModeChanged:    modeChanged,
+			ContentChanged: contentChanged,
+		})
+		r.Changed++
+	}
+
+	for p, n := range after.Files {
+		if _, ok := before.Files[p]; ok {
+			continue
+		}
+		r.Files = append(r.Files, FileChange{
+			Path:      p,
+			Type:      Added,
+			NewSize:   n.Size,
+			SizeDelta: n.Size,
+			NewMode:   n.Mode.String(),
+		})
+		r.Added++
+	}
+
+	for k, o := range before.Metadata {
+		n, ok := after.Metadata[k]
+		switch {
+		case !ok:
+			r.Metadata = append(r.Metadata, MetadataChange{Key: k, Type: Removed, Old: o})
+		case o != n:
+			r.Metadata = append(r.Metadata, MetadataChange{Key: k, Type: Changed, Old: o, New: n})
+		}
+	}
+	for k, n := range after.Metadata {
+		if _, ok := before.Metadata[k]; !ok {
+			r.Metadata = append(r.Metadata, MetadataChange{Key: k, Type: Added, New: n})
+		}
+	}
+
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+	sort.Slice(r.Metadata, func(i, j int) bool { return r.Metadata[i].Key < r.Metadata[j].Key })
+
+	return r
+}
@@ -0,0 +1,119 @@
+package diff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/diff"
+)
+
+func TestCompare(t *testing.T) {
+	before := &diff.Contents{
+		Type: diff.TypeDeb,
+		Size: 1000,
+		Files: map[string]diff.File{
+			"usr/sbin/grafana":   {Path: "usr/sbin/grafana", Size: 100, Mode: 0o755, Sha256: "a"},
+			"usr/sbin/grafana-x": {Path: "usr/sbin/grafana-x", Size: 10, Mode: 0o755, Sha256: "b"},
+			"etc/grafana.ini":    {Path: "etc/grafana.ini", Size: 5, Mode: 0o644, Sha256: "c"},
+			"usr/share/README":   {Path: "usr/share/README", Size: 7, Mode: 0o644, Sha256: "d"},
+		},
+		Metadata: map[string]string{
+			"Version": "10.1.0",
+			"Depends": "adduser",
+		},
+	}
+	after := &diff.Contents{
+		Type: diff.TypeDeb,
+		Size: 1200,
+		Files: map[string]diff.File{
+			"usr/sbin/grafana": {Path: "usr/sbin/grafana", Size: 150, Mode: 0o755, Sha256: "e"},
+			"etc/grafana.ini":  {Path: "etc/grafana.ini", Size: 5, Mode: 0o600, Sha256: "c"},
+			"usr/share/README": {Path: "usr/share/README", Size: 7, Mode: 0o644, Sha256: "d"},
+			"usr/sbin/new":     {Path: "usr/sbin/new", Size: 20, Mode: 0o755, Sha256: "f"},
+		},
+		Metadata: map[string]string{
+			"Version":    "10.2.0",
+			"Recommends": "fontconfig",
+		},
+	}
+
+	r := diff.Compare("old.deb", before, "new.deb", after)
+	if r.Added != 1 || r.Removed != 1 || r.Changed != 2 {
+		t.Errorf("expected 1 added, 1 removed, and 2 changed files but got %d, %d, %d", r.Added, r.Removed, r.Changed)
+	}
+	if r.SizeDelta != 200 {
+		t.Errorf("expected a size delta of 200 but got %d", r.SizeDelta)
+	}
+
+	expectFiles := []diff.FileChange{
+		{Path: "etc/grafana.ini", Type: diff.Changed, OldSize: 5, NewSize: 5, OldMode: fs.FileMode(0o644).String(), NewMode: fs.FileMode(0o600).String(), ModeChanged: true},
+		{Path: "usr/sbin/grafana", Type: diff.Changed, OldSize: 100, NewSize: 150, SizeDelta: 50, OldMode: "-rwxr-xr-x", NewMode: "-rwxr-xr-x", ContentChanged: true},
+		{Path: "usr/sbin/grafana-x", Type: diff.Removed, OldSize: 10, SizeDelta: -10, OldMode: "-rwxr-xr-x"},
+		{Path: "usr/sbin/new", Type: diff.Added, NewSize: 20, SizeDelta: 20, NewMode: "-rwxr-xr-x"},
+	}
+	if len(r.Files) != len(expectFiles) {
+		t.Fatalf("expected %d file changes but got %+v", len(expectFiles), r.Files)
+	}
+	for i, v := range expectFiles {
+		if r.Files[i] != v {
+			t.Errorf("expected file change %d to be %+v but got %+v", i, v, r.Files[i])
+		}
+	}
+
+	expectMetadata := []diff.MetadataChange{
+		{Key: "Depends", Type: diff.Removed, Old: "adduser"},
+		{Key: "Recommends", Type: diff.Added, New: "fontconfig"},
+		{Key: "Version", Type: diff.Changed, Old: "10.1.0", New: "10.2.0"},
+	}
+	if len(r.Metadata) != len(expectMetadata) {
+		t.Fatalf("expected %d metadata changes but got %+v", len(expectMetadata), r.Metadata)
+	}
+	for i, v := range expectMetadata {
+		if r.Metadata[i] != v {
+			t.Errorf("expected metadata change %d to be %+v but got %+v", i, v, r.Metadata[i])
+		}
+	}
+
+	text := &bytes.Buffer{}
+	if err := diff.WriteText(text, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{
+		"1 added, 1 removed, 2 changed files, 3 metadata changes",
+		"~ Version: 10.1.0 -> 10.2.0",
+		"-rw-r--r-- -> -rw-------",
+		"+ usr/sbin/new",
+	} {
+		if !strings.Contains(text.String(), v) {
+			t.Errorf("expected text output to contain '%s':\n%s", v, text.String())
+		}
+	}
+
+	js := &bytes.Buffer{}
+	if err := diff.WriteJSON(js, r); err != nil {
+		t.Fatal(err)
+	}
+	decoded := &diff.Result{}
+	if err := json.Unmarshal(js.Bytes(), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Added != 1 || len(decoded.Files) != 4 || decoded.Files[0].ModeChanged != true {
+		t.Errorf("unexpected JSON output: %s", js.String())
+	}
+}
+
+func TestCompareEmpty(t *testing.T) {
+	c := &diff.Contents{
+		Type:     diff.TypeTarGZ,
+		Files:    map[string]diff.File{"bin/grafana": {Path: "bin/grafana", Size: 1, Mode: 0o755, Sha256: "a"}},
+		Metadata: map[string]string{},
+	}
+
+	r := diff.Compare("a.tar.gz", c, "b.tar.gz", c)
+	if !r.Empty() {
+		t.Errorf("expected no differences but got %+v", r)
+	}
+}
@@ -0,0 +1,183 @@
+package diff
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// dockerManifest is an entry in the 'manifest.json' of an archive created with 'docker save'.
+type dockerManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// dockerConfig is the part of the image config that is compared as metadata.
+type dockerConfig struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Config       struct {
+		User         string              `json:"User"`
+		Env          []string            `json:"Env"`
+		Entrypoint   []string            `json:"Entrypoint"`
+		Cmd          []string            `json:"Cmd"`
+		WorkingDir   string              `json:"WorkingDir"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+		Volumes      map[string]struct{} `json:"Volumes"`
+		Labels       map[string]string   `json:"Labels"`
+	} `json:"config"`
+}
+
+// metadata returns the config as flat metadata, like 'Env.GF_PATHS_HOME' or 'Labels.org.opencontainers.image.version'.
+func (c *dockerConfig) metadata(m map[string]string) {
+	set := func(k, v string) {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	keys := func(v map[string]struct{}) string {
+		k := make([]string, 0, len(v))
+		for key := range v {
+			k = append(k, key)
+		}
+		sort.Strings(k)
+		return strings.Join(k, " ")
+	}
+
+	set("Architecture", c.Architecture)
+	set("OS", c.OS)
+	set("User", c.Config.User)
+	set("WorkingDir", c.Config.WorkingDir)
+	set("Entrypoint", strings.Join(c.Config.Entrypoint, " "))
+	set("Cmd", strings.Join(c.Config.Cmd, " "))
+	set("ExposedPorts", keys(c.Config.ExposedPorts))
+	set("Volumes", keys(c.Config.Volumes))
+	for _, env := range c.Config.Env {
+		k, v, _ := strings.Cut(env, "=")
+		m["Env."+k] = v
+	}
+	for k, v := range c.Config.Labels {
+		m["Labels."+k] = v
+	}
+}
+
+// ReadDocker reads the files and the image config of an archive created with 'docker save'.
+// The files are the result of applying every layer in order, including the whiteout files that remove files of previous layers.
+func ReadDocker(r io.Reader) (*Contents, error) {
+	var (
+		// Small JSON files, like the manifest and the image config.
+		jsonFiles = map[string][]byte{}
+		// The files in every layer by the path of the layer. The manifest is usually at the end of the archive, so
+		// the layers are read in the order that they appear in and applied in the order of the manifest afterwards.
+		layers = map[string][]File{}
+	)
+
+	err := walkTar(r, func(h *tar.Header, r io.Reader) error {
+		if h.Typeflag != tar.TypeReg {
+			return nil
+		}
+		name := cleanPath(h.Name)
+		// Images saved in the OCI layout store the config as a blob without a '.json' extension.
+		br := bufio.NewReader(r)
+		r = br
+		if start, _ := br.Peek(1); strings.HasSuffix(name, ".json") || bytes.Equal(start, []byte("{")) {
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			jsonFiles[name] = b
+			return nil
+		}
+
+		// Every other file should be a layer. Anything that isn't a tar archive is ignored.
+		var files []File
+		if err := walkTar(r, func(h *tar.Header, r io.Reader) error {
+			f, err := readFile(h.Name, h.FileInfo().Mode(), h.Linkname, r)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+			return nil
+		}); err != nil {
+			return nil
+		}
+		layers[name] = files
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := jsonFiles["manifest.json"]
+	if !ok {
+		return nil, errors.New("manifest.json not found; is this an archive created with 'docker save'?")
+	}
+	var manifests []dockerManifest
+	if err := json.Unmarshal(b, &manifests); err != nil {
+		return nil, fmt.Errorf("error parsing manifest.json: %w", err)
+	}
+	if len(manifests) != 1 {
+		return nil, fmt.Errorf("expected 1 image in manifest.json but found %d", len(manifests))
+	}
+	manifest := manifests[0]
+
+	c := newContents(TypeDocker)
+	if b, ok := jsonFiles[cleanPath(manifest.Config)]; ok {
+		config := &dockerConfig{}
+		if err := json.Unmarshal(b, config); err != nil {
+			return nil, fmt.Errorf("error parsing image config: %w", err)
+		}
+		config.metadata(c.Metadata)
+	}
+
+	for _, l := range manifest.Layers {
+		files, ok := layers[cleanPath(l)]
+		if !ok {
+			return nil, fmt.Errorf("layer '%s' not found", l)
+		}
+		c.applyLayer(files)
+	}
+
+	return c, nil
+}
+
+// applyLayer adds the files of a layer, removing the files that are marked with whiteout files.
+func (c *Contents) applyLayer(files []File) {
+	for _, f := range files {
+		p := cleanPath(f.Path)
+		dir, base := path.Split(p)
+		dir = strings.TrimSuffix(dir, "/")
+
+		switch {
+		case base == ".wh..wh..opq":
+			// The directory is opaque; everything in it from previous layers is removed.
+			c.removeChildren(dir)
+		case strings.HasPrefix(base, ".wh."):
+			removed := path.Join(dir, strings.TrimPrefix(base, ".wh."))
+			delete(c.Files, removed)
+			c.removeChildren(removed)
+		default:
+			c.add(f)
+		}
+	}
+}
+
+func (c *Contents) removeChildren(dir string) {
+	prefix := dir + "/"
+	if dir == "" {
+		prefix = ""
+	}
+	for p := range c.Files {
+		if strings.HasPrefix(p, prefix) && p != dir {
+			delete(c.Files, p)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grafana/grafana-build/stringutil"
+)
+
+var changeSymbols = map[ChangeType]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+func formatDelta(b int64) string {
+	if b >= 0 {
+		return "+" + stringutil.FormatSize(b)
+	}
+	return stringutil.FormatSize(b)
+}
+
+// formatValue quotes metadata values that span multiple lines, like descriptions or lists of dependencies, so that they stay on one line.
+func formatValue(v string) string {
+	if strings.ContainsAny(v, "\n\t") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// WriteText writes the result in a format that is meant to be read by people.
+func WriteText(w io.Writer, r *Result) error {
+	fmt.Fprintf(w, "--- %s (%s)\n", r.Old, stringutil.FormatSize(r.OldSize))
+	fmt.Fprintf(w, "+++ %s (%s, %s)\n\n", r.New, stringutil.FormatSize(r.NewSize), formatDelta(r.SizeDelta))
+
+	if r.Empty() {
+		_, err := fmt.Fprintln(w, "No differences in files or metadata")
+		return err
+	}
+
+	fmt.Fprintf(w, "%d added, %d removed, %d changed files, %d metadata changes\n", r.Added, r.Removed, r.Changed, len(r.Metadata))
+
+	if len(r.Metadata) != 0 {
+		fmt.Fprint(w, "\nMetadata:\n")
+		for _, m := range r.Metadata {
+			switch m.Type {
+			case Added:
+				fmt.Fprintf(w, "  + %s: %s\n", m.Key, formatValue(m.New))
+			case Removed:
+				fmt.Fprintf(w, "  - %s: %s\n", m.Key, formatValue(m.Old))
+			case Changed:
+				fmt.Fprintf(w, "  ~ %s: %s -> %s\n", m.Key, formatValue(m.Old), formatValue(m.New))
+			}
+		}
+	}
+
+	if len(r.Files) != 0 {
+		fmt.Fprint(w, "\nFiles:\n")
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, f := range r.Files {
+			size, mode := stringutil.FormatSize(f.NewSize), f.NewMode
+			switch f.Type {
+			case Removed:
+				size, mode = stringutil.FormatSize(f.OldSize), f.OldMode
+			case Changed:
+				if f.ModeChanged {
+					mode = f.OldMode + " -> " + f.NewMode
+				}
+			}
+			fmt.Fprintf(tw, "  %s %s\t%s\t%s\t%s\n", changeSymbols[f.Type], f.Path, size, formatDelta(f.SizeDelta), mode)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes the result as indented JSON, for example to decide whether a release can be published.
+func WriteJSON(w io.Writer, r *Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
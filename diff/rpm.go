@@ -0,0 +1,247 @@
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+var rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8}
+
+// rpm header tags that are compared as package metadata.
+var rpmTags = map[uint32]string{
+	1000: "Name",
+	1001: "Version",
+	1002: "Release",
+	1004: "Summary",
+	1005: "Description",
+	1011: "Vendor",
+	1014: "License",
+	1016: "Group",
+	1020: "URL",
+	1021: "OS",
+	1022: "Arch",
+	1047: "Provides",
+	1049: "Requires",
+	1054: "Conflicts",
+	1090: "Obsoletes",
+}
+
+// rpm header tags of the scriptlets, which are added as files in 'SCRIPTS/'.
+var rpmScripts = map[uint32]string{
+	1023: "prein",
+	1024: "postin",
+	1025: "preun",
+	1026: "postun",
+}
+
+const (
+	rpmTagPayloadCompressor = 1125
+
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+type rpmEntry struct {
+	Tag, Type, Offset, Count uint32
+}
+
+// readRPMHeader reads an rpm header structure and returns its string values by tag.
+// If pad is true, then the padding to an 8-byte boundary after the signature header is skipped.
+func readRPMHeader(r io.Reader, pad bool) (map[uint32][]string, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(intro[:3], rpmHeaderMagic) {
+		return nil, errors.New("invalid rpm header")
+	}
+
+	var (
+		count = binary.BigEndian.Uint32(intro[8:12])
+		size  = binary.BigEndian.Uint32(intro[12:16])
+	)
+	entries := make([]rpmEntry, count)
+	if err := binary.Read(r, binary.BigEndian, entries); err != nil {
+		return nil, err
+	}
+
+	store := make([]byte, size)
+	if _, err := io.ReadFull(r, store); err != nil {
+		return nil, err
+	}
+	if pad && size%8 != 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(8-size%8)); err != nil {
+			return nil, err
+		}
+	}
+
+	values := map[uint32][]string{}
+	for _, e := range entries {
+		if e.Offset >= size {
+			continue
+		}
+		data := store[e.Offset:]
+		switch e.Type {
+		case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+			n := e.Count
+			if e.Type == rpmTypeString {
+				n = 1
+			}
+			strs := strings.SplitN(string(data), "\x00", int(n)+1)
+			if uint32(len(strs)) > n {
+				strs = strs[:n]
+			}
+			values[e.Tag] = strs
+		case rpmTypeInt32:
+			var ints []string
+			for i := uint32(0); i < e.Count && int(i*4+4) <= len(data); i++ {
+				ints = append(ints, strconv.FormatUint(uint64(binary.BigEndian.Uint32(data[i*4:])), 10))
+			}
+			values[e.Tag] = ints
+		}
+	}
+
+	return values, nil
+}
+
+// walkCPIO calls fn with every file in a cpio archive in the 'newc' format, which is used for rpm payloads.
+func walkCPIO(r io.Reader, fn func(File) error) error {
+	br := bufio.NewReader(r)
+	header := make([]byte, 110)
+	var offset int64
+	// Names and file contents are padded to a multiple of 4 bytes.
+	align := func() error {
+		pad := (4 - offset%4) % 4
+		_, err := br.Discard(int(pad))
+		offset += pad
+		return err
+	}
+
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			return err
+		}
+		offset += 110
+		if magic := string(header[:6]); magic != "070701" && magic != "070702" {
+			return fmt.Errorf("unsupported cpio format '%s'", magic)
+		}
+
+		field := func(i int) (int64, error) {
+			return strconv.ParseInt(string(header[6+i*8:14+i*8]), 16, 64)
+		}
+		mode, err := field(1)
+		if err != nil {
+			return err
+		}
+		size, err := field(6)
+		if err != nil {
+			return err
+		}
+		nameSize, err := field(11)
+		if err != nil {
+			return err
+		}
+
+		name := make([]byte, nameSize)
+		if _, err := io.ReadFull(br, name); err != nil {
+			return err
+		}
+		offset += nameSize
+		if err := align(); err != nil {
+			return err
+		}
+
+		filename := strings.TrimSuffix(string(name), "\x00")
+		if filename == "TRAILER!!!" {
+			return nil
+		}
+
+		data := io.LimitReader(br, size)
+		fileMode := unixMode(uint32(mode))
+		var link string
+		if fileMode&fs.ModeSymlink != 0 {
+			b, err := io.ReadAll(data)
+			if err != nil {
+				return err
+			}
+			link = string(b)
+		}
+
+		f, err := readFile(filename, fileMode, link, data)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, data); err != nil {
+			return err
+		}
+		offset += size
+		if err := align(); err != nil {
+			return err
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadRPM reads the files and the headers of an rpm package.
+// The scriptlets, like 'postin', are added as files in 'SCRIPTS/'.
+// Only gzipped or uncompressed payloads are supported.
+func ReadRPM(r io.Reader) (*Contents, error) {
+	br := bufio.NewReader(r)
+
+	// The lead is a fixed-size, legacy header that is replaced by the headers after it.
+	if _, err := io.CopyN(io.Discard, br, 96); err != nil {
+		return nil, fmt.Errorf("error reading rpm lead: %w", err)
+	}
+	if _, err := readRPMHeader(br, true); err != nil {
+		return nil, fmt.Errorf("error reading rpm signature: %w", err)
+	}
+	header, err := readRPMHeader(br, false)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rpm header: %w", err)
+	}
+
+	c := newContents(TypeRPM)
+	for tag, name := range rpmTags {
+		if v, ok := header[tag]; ok {
+			c.Metadata[name] = strings.Join(v, "\n")
+		}
+	}
+	for tag, name := range rpmScripts {
+		if v, ok := header[tag]; ok && len(v) != 0 {
+			f, err := readFile("SCRIPTS/"+name, 0o755, "", strings.NewReader(v[0]))
+			if err != nil {
+				return nil, err
+			}
+			c.add(f)
+		}
+	}
+
+	if compressor := header[rpmTagPayloadCompressor]; len(compressor) != 0 && compressor[0] != "gzip" && compressor[0] != "" {
+		return nil, fmt.Errorf("unsupported payload compression '%s'", compressor[0])
+	}
+	payload, err := decompress(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := walkCPIO(payload, func(f File) error {
+		c.add(f)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error reading rpm payload: %w", err)
+	}
+
+	return c, nil
+}
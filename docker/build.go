@@ -24,14 +24,21 @@ type BuildOpts struct {
 	Platform dagger.Platform
 }
 
-func Builder(d *dagger.Client, socket *dagger.Socket, targz *dagger.File) *dagger.Container {
+// Builder returns a container with the docker CLI, the tar.gz package, and the Dockerfile of the variant.
+// Variants without their own Dockerfile use the Dockerfile in the tar.gz package.
+func Builder(d *dagger.Client, socket *dagger.Socket, targz *dagger.File, variant Variant) *dagger.Container {
 	extracted := containers.ExtractedArchive(d, targz)
 
+	dockerfile := extracted.File("Dockerfile")
+	if variant.Dockerfile != "" {
+		dockerfile = d.Directory().WithNewFile("Dockerfile", variant.Dockerfile).File("Dockerfile")
+	}
+
 	// Instead of supplying the Platform argument here, we need to tell the host docker socket that it needs to build with the given platform.
 	return d.Container().From("docker").
 		WithUnixSocket("/var/run/docker.sock", socket).
 		WithWorkdir("/src").
-		WithMountedFile("/src/Dockerfile", extracted.File("Dockerfile")).
+		WithMountedFile("/src/Dockerfile", dockerfile).
 		WithMountedFile("/src/packaging/docker/run.sh", extracted.File("packaging/docker/run.sh")).
 		WithMountedFile("/src/grafana.tar.gz", targz)
 }
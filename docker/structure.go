@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// ImageConfig is the subset of an image's configuration that is validated by VerifyStructure.
+type ImageConfig struct {
+	User         string
+	Entrypoint   []string
+	Env          map[string]string
+	ExposedPorts []int
+}
+
+// ImageExpectations describes what a Grafana docker image should look like once it has been built.
+type ImageExpectations struct {
+	// User is the user (or uid) that the image runs as.
+	User string
+	// Username is the name that the User should resolve to inside the container.
+	Username string
+	// Entrypoint is the expected image entrypoint.
+	Entrypoint []string
+	// Env is a map of environment variables that must be set to the given value.
+	Env map[string]string
+	// Ports is a list of TCP ports that must be exposed.
+	Ports []int
+	// Home is the Grafana installation directory.
+	Home string
+	// Entries are files or folders that must exist in the Home directory.
+	Entries []string
+}
+
+// DefaultImageExpectations are the expectations for the alpine and ubuntu images built from packaging/docker/Dockerfile.
+var DefaultImageExpectations = ImageExpectations{
+	User:       "472",
+	Username:   "grafana",
+	Entrypoint: []string{"/run.sh"},
+	Env: map[string]string{
+		"GF_PATHS_CONFIG":       "/etc/grafana/grafana.ini",
+		"GF_PATHS_DATA":         "/var/lib/grafana",
+		"GF_PATHS_HOME":         "/usr/share/grafana",
+		"GF_PATHS_LOGS":         "/var/log/grafana",
+		"GF_PATHS_PLUGINS":      "/var/lib/grafana/plugins",
+		"GF_PATHS_PROVISIONING": "/etc/grafana/provisioning",
+	},
+	Ports:   []int{3000},
+	Home:    "/usr/share/grafana",
+	Entries: []string{"LICENSE", "bin", "conf", "public"},
+}
+
+// Validate compares the image configuration against the expectations and returns an error describing every mismatch.
+func (e ImageExpectations) Validate(cfg ImageConfig) error {
+	var errs []error
+	if e.User != "" && cfg.User != e.User {
+		errs = append(errs, fmt.Errorf("expected user '%s' but got '%s'", e.User, cfg.User))
+	}
+
+	if e.Entrypoint != nil && !slices.Equal(cfg.Entrypoint, e.Entrypoint) {
+		errs = append(errs, fmt.Errorf("expected entrypoint %v but got %v", e.Entrypoint, cfg.Entrypoint))
+	}
+
+	keys := make([]string, 0, len(e.Env))
+	for k := range e.Env {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		v, ok := cfg.Env[k]
+		if !ok {
+			errs = append(errs, fmt.Errorf("expected environment variable '%s' to be set", k))
+			continue
+		}
+		if v != e.Env[k] {
+			errs = append(errs, fmt.Errorf("expected environment variable '%s' to be '%s' but got '%s'", k, e.Env[k], v))
+		}
+	}
+
+	for _, p := range e.Ports {
+		if !slices.Contains(cfg.ExposedPorts, p) {
+			errs = append(errs, fmt.Errorf("expected port %d to be exposed", p))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateEntries returns an error for each expected entry that is missing from the list of entries in the Home directory.
+func (e ImageExpectations) ValidateEntries(entries []string) error {
+	var errs []error
+	for _, v := range e.Entries {
+		if !slices.Contains(entries, v) {
+			errs = append(errs, fmt.Errorf("expected '%s' to exist in '%s'", v, e.Home))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// InspectImage reads the ImageConfig from the given container.
+func InspectImage(ctx context.Context, container *dagger.Container) (ImageConfig, error) {
+	cfg := ImageConfig{
+		Env: map[string]string{},
+	}
+
+	user, err := container.User(ctx)
+	if err != nil {
+		return cfg, fmt.Errorf("error getting image user: %w", err)
+	}
+	cfg.User = user
+
+	entrypoint, err := container.Entrypoint(ctx)
+	if err != nil {
+		return cfg, fmt.Errorf("error getting image entrypoint: %w", err)
+	}
+	cfg.Entrypoint = entrypoint
+
+	env, err := container.EnvVariables(ctx)
+	if err != nil {
+		return cfg, fmt.Errorf("error getting image environment variables: %w", err)
+	}
+	for _, v := range env {
+		name, err := v.Name(ctx)
+		if err != nil {
+			return cfg, err
+		}
+		value, err := v.Value(ctx)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Env[name] = value
+	}
+
+	ports, err := container.ExposedPorts(ctx)
+	if err != nil {
+		return cfg, fmt.Errorf("error getting image exposed ports: %w", err)
+	}
+	for _, v := range ports {
+		port, err := v.Port(ctx)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.ExposedPorts = append(cfg.ExposedPorts, port)
+	}
+
+	return cfg, nil
+}
+
+// VerifyStructure validates the configuration and filesystem layout of the image in the container against the expectations.
+// The container should be created by importing the image, and must not have been modified.
+func VerifyStructure(ctx context.Context, container *dagger.Container, e ImageExpectations) error {
+	cfg, err := InspectImage(ctx, container)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Validate(cfg); err != nil {
+		return fmt.Errorf("image configuration is invalid: %w", err)
+	}
+
+	entries, err := container.Directory(e.Home).Entries(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing '%s': %w", e.Home, err)
+	}
+
+	if err := e.ValidateEntries(entries); err != nil {
+		return fmt.Errorf("image layout is invalid: %w", err)
+	}
+
+	if e.Username == "" {
+		return nil
+	}
+
+	username, err := container.
+		WithEntrypoint([]string{}).
+		WithExec([]string{"id", "-un"}).
+		Stdout(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting username for user '%s': %w", e.User, err)
+	}
+
+	if u := strings.TrimSpace(username); u != e.Username {
+		return fmt.Errorf("expected user '%s' to be '%s' but got '%s'", e.User, e.Username, u)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package docker_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/docker"
+)
+
+func validConfig() docker.ImageConfig {
+	return docker.ImageConfig{
+		User:         "472",
+		Entrypoint:   []string{"/run.sh"},
+		Env:          maps.Clone(docker.DefaultImageExpectations.Env),
+		ExposedPorts: []int{3000},
+	}
+}
+
+func TestImageExpectationsValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		if err := docker.DefaultImageExpectations.Validate(validConfig()); err != nil {
+			t.Fatalf("expected no error but got %s", err)
+		}
+	})
+
+	cases := map[string]struct {
+		modify func(cfg *docker.ImageConfig)
+		expect string
+	}{
+		"root user": {
+			modify: func(cfg *docker.ImageConfig) { cfg.User = "" },
+			expect: "expected user '472'",
+		},
+		"entrypoint": {
+			modify: func(cfg *docker.ImageConfig) { cfg.Entrypoint = []string{"/bin/sh"} },
+			expect: "expected entrypoint [/run.sh]",
+		},
+		"missing env": {
+			modify: func(cfg *docker.ImageConfig) { delete(cfg.Env, "GF_PATHS_DATA") },
+			expect: "'GF_PATHS_DATA' to be set",
+		},
+		"wrong env": {
+			modify: func(cfg *docker.ImageConfig) { cfg.Env["GF_PATHS_HOME"] = "/opt/grafana" },
+			expect: "'GF_PATHS_HOME' to be '/usr/share/grafana' but got '/opt/grafana'",
+		},
+		"port": {
+			modify: func(cfg *docker.ImageConfig) { cfg.ExposedPorts = []int{8080} },
+			expect: "expected port 3000 to be exposed",
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			cfg := validConfig()
+			v.modify(&cfg)
+			err := docker.DefaultImageExpectations.Validate(cfg)
+			if err == nil {
+				t.Fatalf("expected an error but got nil")
+			}
+			if !strings.Contains(err.Error(), v.expect) {
+				t.Errorf("expected error to contain '%s' but got '%s'", v.expect, err.Error())
+			}
+		})
+	}
+}
+
+func TestImageExpectationsValidateEntries(t *testing.T) {
+	if err := docker.DefaultImageExpectations.ValidateEntries([]string{"LICENSE", "bin", "conf", "public", "plugins-bundled"}); err != nil {
+		t.Errorf("expected no error but got %s", err)
+	}
+
+	err := docker.DefaultImageExpectations.ValidateEntries([]string{"bin", "conf", "public"})
+	if err == nil || !strings.Contains(err.Error(), "expected 'LICENSE' to exist in '/usr/share/grafana'") {
+		t.Errorf("expected missing LICENSE error but got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package docker
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+var (
+	//go:embed dockerfiles/distroless.Dockerfile
+	distrolessDockerfile string
+
+	//go:embed dockerfiles/ubi.Dockerfile
+	ubiDockerfile string
+)
+
+// A Variant is a base image that the Grafana docker image can be built on.
+// Each variant produces an image file with its own extension, so that multiple variants can be built and published for the same package.
+// Adding a variant to Variants adds its 'base=<name>' artifact flag and its base image and tag format arguments.
+type Variant struct {
+	// Name is used in the 'base=<name>' artifact flag.
+	Name string
+	// Ext is the extension of the file created with 'docker save', like 'docker.tar.gz' or 'ubuntu.docker.tar.gz'.
+	Ext string
+	// Suffix is added to the name of the image when it is published, like '-ubuntu'. The default variant has no suffix.
+	Suffix string
+
+	// ImageFlag is the name of the flag that sets the base image, like 'alpine-base', and DefaultImage is its default value.
+	ImageFlag    string
+	DefaultImage string
+	// TagFormatFlag is the name of the flag that sets the go template of the image tags, like 'tag-format', and DefaultTagFormat is its default value.
+	TagFormatFlag    string
+	DefaultTagFormat string
+
+	// Dockerfile is the Dockerfile that the image is built with. If it's empty, then the Dockerfile in the tar.gz package is used.
+	Dockerfile string
+	// Expectations are used when verifying the image.
+	Expectations ImageExpectations
+}
+
+var (
+	VariantAlpine = Variant{
+		Name:             "alpine",
+		Ext:              "docker.tar.gz",
+		ImageFlag:        "alpine-base",
+		DefaultImage:     "alpine:latest",
+		TagFormatFlag:    "tag-format",
+		DefaultTagFormat: DefaultTagFormat,
+		Expectations:     DefaultImageExpectations,
+	}
+	VariantUbuntu = Variant{
+		Name:             "ubuntu",
+		Ext:              "ubuntu.docker.tar.gz",
+		Suffix:           "-ubuntu",
+		ImageFlag:        "ubuntu-base",
+		DefaultImage:     "ubuntu:latest",
+		TagFormatFlag:    "ubuntu-tag-format",
+		DefaultTagFormat: DefaultUbuntuTagFormat,
+		Expectations:     DefaultImageExpectations,
+	}
+	VariantDistroless = Variant{
+		Name:             "distroless",
+		Ext:              "distroless.docker.tar.gz",
+		Suffix:           "-distroless",
+		ImageFlag:        "distroless-base",
+		DefaultImage:     "gcr.io/distroless/base-debian12:latest",
+		TagFormatFlag:    "distroless-tag-format",
+		DefaultTagFormat: "{{ .version }}-distroless-{{ .arch }}",
+		Dockerfile:       distrolessDockerfile,
+		Expectations:     distrolessExpectations(),
+	}
+	VariantUBI = Variant{
+		Name:             "ubi",
+		Ext:              "ubi.docker.tar.gz",
+		Suffix:           "-ubi",
+		ImageFlag:        "ubi-base",
+		DefaultImage:     "registry.access.redhat.com/ubi9/ubi-minimal:latest",
+		TagFormatFlag:    "ubi-tag-format",
+		DefaultTagFormat: "{{ .version }}-ubi-{{ .arch }}",
+		Dockerfile:       ubiDockerfile,
+		Expectations:     DefaultImageExpectations,
+	}
+)
+
+// Variants is the list of all supported base image variants. The first variant is the default.
+var Variants = []Variant{
+	VariantAlpine,
+	VariantUbuntu,
+	VariantDistroless,
+	VariantUBI,
+}
+
+// Distroless images don't have a shell, so they run the grafana binary instead of 'run.sh', and the user name can't be checked with 'id'.
+func distrolessExpectations() ImageExpectations {
+	e := DefaultImageExpectations
+	e.Username = ""
+	e.Entrypoint = []string{
+		"/usr/share/grafana/bin/grafana", "server",
+		"--homepath=/usr/share/grafana",
+		"--config=/etc/grafana/grafana.ini",
+		"--packaging=docker",
+		"cfg:default.log.mode=console",
+		"cfg:default.paths.data=/var/lib/grafana",
+		"cfg:default.paths.logs=/var/log/grafana",
+		"cfg:default.paths.plugins=/var/lib/grafana/plugins",
+		"cfg:default.paths.provisioning=/etc/grafana/provisioning",
+	}
+	return e
+}
+
+// VariantByName returns the Variant with the given name.
+func VariantByName(name string) (Variant, error) {
+	for _, v := range Variants {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+
+	return Variant{}, fmt.Errorf("unrecognized docker base '%s'", name)
+}
+
+// VariantFromFileName returns the Variant that produced the given image file, based on its extension.
+// A '.sha256' extension is ignored. If no other variant matches, then the default variant is returned.
+func VariantFromFileName(name string) Variant {
+	name = strings.TrimSuffix(name, ".sha256")
+	for _, v := range Variants[1:] {
+		if strings.HasSuffix(name, "."+v.Ext) {
+			return v
+		}
+	}
+
+	return Variants[0]
+}
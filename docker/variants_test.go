@@ -0,0 +1,57 @@
+package docker_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/docker"
+)
+
+func TestVariantFromFileName(t *testing.T) {
+	cases := map[string]string{
+		"grafana_v1.2.3_102_linux_amd64.docker.tar.gz":                   "alpine",
+		"grafana_v1.2.3_102_linux_amd64.ubuntu.docker.tar.gz":            "ubuntu",
+		"grafana_v1.2.3_102_linux_amd64.distroless.docker.tar.gz.sha256": "distroless",
+		"grafana-enterprise_v1.2.3_102_linux_arm64.ubi.docker.tar.gz":    "ubi",
+	}
+
+	for k, v := range cases {
+		if name := docker.VariantFromFileName(k).Name; name != v {
+			t.Errorf("expected '%s' to be the '%s' variant but got '%s'", k, v, name)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	flags := map[string]bool{}
+	for _, v := range docker.Variants {
+		if _, err := docker.VariantByName(v.Name); err != nil {
+			t.Errorf("expected to find variant '%s' by name but got %s", v.Name, err)
+		}
+
+		for _, f := range []string{v.ImageFlag, v.TagFormatFlag} {
+			if f == "" || flags[f] {
+				t.Errorf("expected variant '%s' to have its own flag but got '%s'", v.Name, f)
+			}
+			flags[f] = true
+		}
+
+		if v.Dockerfile == "" {
+			continue
+		}
+
+		// The build arguments of docker.Build select the base image and the package.
+		for _, arg := range []string{"ARG BASE_IMAGE=", "ARG GRAFANA_TGZ="} {
+			if !strings.Contains(v.Dockerfile, arg) {
+				t.Errorf("expected the Dockerfile of variant '%s' to have '%s'", v.Name, arg)
+			}
+		}
+		if !strings.Contains(v.Dockerfile, `ENTRYPOINT [ "`+v.Expectations.Entrypoint[0]+`"`) {
+			t.Errorf("expected the Dockerfile of variant '%s' to have the entrypoint %v", v.Name, v.Expectations.Entrypoint)
+		}
+	}
+
+	if _, err := docker.VariantByName("scratch"); err == nil {
+		t.Error("expected an error for an unrecognized variant")
+	}
+}
@@ -12,7 +12,8 @@ import (
 )
 
 // Verify uses the given package (.docker.tar.gz) and grafana source code (src) to run the e2e smoke tests.
-// the returned directory is the e2e artifacts created by cypress (screenshots and videos).
+// Before running the e2e tests, the image configuration, layout, LICENSE, and /api/health endpoint are validated so that
+// broken images are found without waiting for cypress.
 func Verify(
 	ctx context.Context,
 	d *dagger.Client,
@@ -20,25 +21,38 @@ func Verify(
 	src *dagger.Directory,
 	yarnCache *dagger.CacheVolume,
 	distro backend.Distribution,
+	enterprise bool,
+	expectations ImageExpectations,
 ) error {
-	nodeVersion, err := frontend.NodeVersion(d, src).Stdout(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get node version from source code: %w", err)
-	}
-
 	var (
 		platform = backend.Platform(distro)
 	)
 
-	// This grafana service runs in the background for the e2e tests
-	service := d.Container(dagger.ContainerOpts{
+	container := d.Container(dagger.ContainerOpts{
 		Platform: platform,
 	}).
-		Import(image).
-		WithExposedPort(3000)
+		Import(image)
+
+	if err := VerifyStructure(ctx, container, expectations); err != nil {
+		return err
+	}
+
+	if err := e2e.ValidateLicense(ctx, container, expectations.Home+"/LICENSE", enterprise); err != nil {
+		return err
+	}
+
+	// This grafana service runs in the background for the e2e tests
+	service := container.WithExposedPort(3000)
+
+	if _, err := e2e.ValidateHealth(ctx, d, service); err != nil {
+		return err
+	}
+
+	nodeVersion, err := frontend.NodeVersion(d, src).Stdout(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node version from source code: %w", err)
+	}
 
-		// TODO: Add LICENSE to containers and implement validation
-	container := e2e.ValidatePackage(d, service, src, yarnCache, nodeVersion)
-	_, err = containers.ExitError(ctx, container)
+	_, err = containers.ExitError(ctx, e2e.ValidatePackage(d, service, src, yarnCache, nodeVersion))
 	return err
 }
@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/frontend"
+)
+
+const (
+	// CoverageResultsDir is the directory in the cypress container where the coverage data and the logs of the e2e tests are written.
+	CoverageResultsDir = "/tmp/e2e-coverage"
+
+	// CoverageGrafanaDir is where the extracted tarball with the instrumented Grafana is mounted.
+	CoverageGrafanaDir = "/grafana"
+
+	// CoverageDataDir is the name of the directory in the results that is used as GOCOVERDIR. It can be read with 'go tool covdata'.
+	CoverageDataDir = "covdata"
+
+	// CoverageExitCodeFile is the name of the file in the results that has the exit code of the e2e tests.
+	CoverageExitCodeFile = "exit-code"
+)
+
+// CoverageCommand returns the shell command that starts the Grafana in the 'grafana' directory in the background with GOCOVERDIR set, runs the e2e tests in 'src' against it,
+// and stops it with SIGTERM so that it writes its coverage counters when it exits.
+// It writes the coverage data, 'grafana.log', 'e2e.log', and the exit code of the tests to the results directory.
+// The command always succeeds so that the results can be exported when the tests fail. If Grafana doesn't become healthy within a minute, then the exit code is 1.
+func CoverageCommand(results, grafana, src string) string {
+	var (
+		exitCode = path.Join(results, CoverageExitCodeFile)
+		health   = `node -e "fetch('http://localhost:3000/api/health').then(r => process.exit(r.ok ? 0 : 1), () => process.exit(1))"`
+	)
+
+	return strings.Join([]string{
+		fmt.Sprintf("mkdir -p %s", path.Join(results, CoverageDataDir)),
+		fmt.Sprintf("GOCOVERDIR=%s %s server --homepath=%s > %s 2>&1 &", path.Join(results, CoverageDataDir), path.Join(grafana, "bin", "grafana"), grafana, path.Join(results, "grafana.log")),
+		"pid=$!",
+		fmt.Sprintf("i=0; until %s; do i=$((i+1)); if [ $i -ge 60 ]; then echo 1 > %s; break; fi; sleep 1; done", health, exitCode),
+		fmt.Sprintf("if [ ! -f %s ]; then (cd %s && ./e2e/verify-release) > %s 2>&1; echo $? > %s; fi", exitCode, src, path.Join(results, "e2e.log"), exitCode),
+		"kill -TERM $pid; wait $pid",
+		"true",
+	}, "\n")
+}
+
+// CoverageBuilder returns the cypress container that the e2e tests are run in, with the Grafana source in '/src' and its dependencies installed.
+func CoverageBuilder(d *dagger.Client, src *dagger.Directory, yarnCacheVolume *dagger.CacheVolume, nodeVersion string) *dagger.Container {
+	c := CypressContainer(d, CypressImage(nodeVersion))
+	c = frontend.WithYarnCache(c, yarnCacheVolume)
+
+	return c.WithDirectory("/src", src).
+		WithWorkdir("/src").
+		WithExec([]string{"yarn", "install", "--immutable"})
+}
+
+// Coverage runs the e2e tests against the instrumented Grafana in the 'grafana' directory, which is an extracted tarball, and returns the results directory.
+func Coverage(builder *dagger.Container, grafana *dagger.Directory) *dagger.Directory {
+	return builder.
+		WithDirectory(CoverageGrafanaDir, grafana).
+		WithEnvVariable("HOST", "localhost").
+		WithEnvVariable("PORT", "3000").
+		WithExec([]string{"/bin/sh", "-c", CoverageCommand(CoverageResultsDir, CoverageGrafanaDir, "/src")}).
+		Directory(CoverageResultsDir)
+}
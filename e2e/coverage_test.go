@@ -0,0 +1,59 @@
+package e2e_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/e2e"
+)
+
+// writeScript writes an executable shell script to path.
+func writeScript(t *testing.T, path, script string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCoverageCommand(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		results = filepath.Join(dir, "results")
+		grafana = filepath.Join(dir, "grafana")
+		src     = filepath.Join(dir, "src")
+		bin     = filepath.Join(dir, "bin")
+	)
+
+	// The instrumented binary writes its counters when it exits after SIGTERM, like Grafana does with '-cover'.
+	writeScript(t, filepath.Join(grafana, "bin", "grafana"), `trap 'echo counters > "$GOCOVERDIR/covcounters"; exit 0' TERM
+while true; do sleep 0.1; done
+`)
+	writeScript(t, filepath.Join(src, "e2e", "verify-release"), "echo 'ran e2e tests'; exit 3\n")
+	writeScript(t, filepath.Join(bin, "node"), "exit 0\n")
+
+	cmd := exec.Command("/bin/sh", "-c", e2e.CoverageCommand(results, grafana, src))
+	cmd.Env = append(os.Environ(), "PATH="+bin+":"+os.Getenv("PATH"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("expected the command to succeed when the tests fail but got %s: %s", err, out)
+	}
+
+	for name, expect := range map[string]string{
+		e2e.CoverageExitCodeFile: "3",
+		"e2e.log":                "ran e2e tests",
+		filepath.Join(e2e.CoverageDataDir, "covcounters"): "counters",
+	} {
+		b, err := os.ReadFile(filepath.Join(results, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := strings.TrimSpace(string(b)); v != expect {
+			t.Errorf("expected '%s' in '%s' but got '%s'", expect, name, v)
+		}
+	}
+}
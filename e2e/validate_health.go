@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/containers"
+)
+
+// Health is the response body of Grafana's /api/health endpoint.
+type Health struct {
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+	Version  string `json:"version"`
+}
+
+// ParseHealth parses the response from /api/health and returns an error if Grafana does not report itself as healthy.
+func ParseHealth(body []byte) (*Health, error) {
+	health := &Health{}
+	if err := json.Unmarshal(body, health); err != nil {
+		return nil, fmt.Errorf("error parsing health response '%s': %w", string(body), err)
+	}
+
+	if health.Database != "ok" {
+		return health, fmt.Errorf("expected database to be 'ok' but got '%s'", health.Database)
+	}
+
+	return health, nil
+}
+
+// ValidateHealth requests /api/health from the grafana service on port 3000 and validates the response.
+// It does not require Node or a browser, so it should be used before ValidatePackage to find broken packages quickly.
+func ValidateHealth(ctx context.Context, d *dagger.Client, service *dagger.Container) (*Health, error) {
+	c := d.Container().From("alpine/curl").
+		WithServiceBinding("grafana", service).
+		WithExec([]string{"curl", "-sSf", "--retry", "30", "--retry-delay", "2", "--retry-connrefused", "--retry-all-errors", "http://grafana:3000/api/health"})
+
+	c, err := containers.ExitError(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting /api/health: %w", err)
+	}
+
+	out, err := c.Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseHealth([]byte(out))
+}
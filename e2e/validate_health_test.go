@@ -0,0 +1,42 @@
+package e2e_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/e2e"
+)
+
+func TestParseHealth(t *testing.T) {
+	cases := map[string]struct {
+		body   string
+		expect string
+	}{
+		"healthy":        {`{"commit": "abc123", "database": "ok", "version": "10.2.0"}`, ""},
+		"unhealthy":      {`{"commit": "abc123", "database": "failing", "version": "10.2.0"}`, "expected database to be 'ok' but got 'failing'"},
+		"no database":    {`{"commit": "abc123", "version": "10.2.0"}`, "expected database to be 'ok' but got ''"},
+		"malformed json": {`<html>Bad Gateway</html>`, "error parsing health response"},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			health, err := e2e.ParseHealth([]byte(v.body))
+			if v.expect == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got %s", err)
+				}
+				if health.Version != "10.2.0" || health.Commit != "abc123" {
+					t.Errorf("expected the version and commit of the response but got %+v", health)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error but got nil")
+			}
+			if !strings.Contains(err.Error(), v.expect) {
+				t.Errorf("expected error containing '%s' but got '%s'", v.expect, err)
+			}
+		})
+	}
+}
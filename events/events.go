@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeStarted   Type = "artifact-started"
+	TypeBuilt     Type = "artifact-built"
+	TypeExported  Type = "artifact-exported"
+	TypeVerified  Type = "artifact-verified"
+	TypeScanned   Type = "artifact-scanned"
+	TypePublished Type = "artifact-published"
+	TypeFailed    Type = "artifact-failed"
+)
+
+// An Event is a single line in the '--events' stream.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type Type      `json:"type"`
+	// Action is the action that failed for 'artifact-failed' events, like 'build', 'export', or 'publish'.
+	Action string `json:"action,omitempty"`
+
+	Artifact    string   `json:"artifact,omitempty"`
+	Filename    string   `json:"filename,omitempty"`
+	Distro      string   `json:"distro,omitempty"`
+	Paths       []string `json:"paths,omitempty"`
+	Destination string   `json:"destination,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	// Duration is the number of seconds that the action took, if it is known.
+	Duration float64 `json:"durationSeconds,omitempty"`
+}
+
+// WithError returns a copy of the event with the error set. A nil error does not change the event.
+func (e Event) WithError(err error) Event {
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// A Writer writes events as newline-delimited JSON. It is safe to use from multiple goroutines.
+type Writer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Write writes the event on its own line. If the event has no time, then the current time is used.
+func (w *Writer) Write(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.enc.Encode(e)
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultWriter *Writer
+)
+
+// SetDefault sets the writer used by Emit. If it is nil, then events are discarded.
+func SetDefault(w *Writer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultWriter = w
+}
+
+// Emit writes the event to the default writer. Errors are ignored so that a broken event stream never fails a build.
+func Emit(e Event) {
+	defaultMu.RLock()
+	w := defaultWriter
+	defaultMu.RUnlock()
+
+	if w == nil {
+		return
+	}
+
+	w.Write(e)
+}
@@ -0,0 +1,58 @@
+package events_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana-build/events"
+)
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := events.NewWriter(buf)
+
+	if err := w.Write(events.Event{Type: events.TypeStarted, Artifact: "targz:linux/amd64", Distro: "linux/amd64"}); err != nil {
+		t.Fatal(err)
+	}
+	e := events.Event{Type: events.TypeFailed, Action: "export", Filename: "grafana.tar.gz"}
+	if err := w.Write(e.WithError(errors.New("exit code 1"))); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []events.Event{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		e := events.Event{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("expected every line to be a JSON event but got '%s': %s", scanner.Text(), err)
+		}
+		lines = append(lines, e)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 events but got %d", len(lines))
+	}
+	if lines[0].Time.IsZero() {
+		t.Errorf("expected the time to be set")
+	}
+	if lines[1].Type != events.TypeFailed || lines[1].Error != "exit code 1" || lines[1].Action != "export" {
+		t.Errorf("unexpected failed event %+v", lines[1])
+	}
+}
+
+func TestEmit(t *testing.T) {
+	// Emit without a default writer should not panic.
+	events.Emit(events.Event{Type: events.TypeBuilt})
+
+	buf := &bytes.Buffer{}
+	events.SetDefault(events.NewWriter(buf))
+	defer events.SetDefault(nil)
+
+	events.Emit(events.Event{Type: events.TypePublished, Destination: "gs://bucket/grafana.tar.gz"})
+	if !bytes.Contains(buf.Bytes(), []byte(`"type":"artifact-published"`)) {
+		t.Errorf("expected the event to be written but got '%s'", buf.String())
+	}
+}
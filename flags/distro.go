@@ -9,54 +9,26 @@ const (
 	FlagDistribution = "distro"
 )
 
-var StaticDistributions = []backend.Distribution{
-	backend.DistLinuxAMD64,
-	backend.DistLinuxARM64,
-	backend.DistLinuxARMv7,
-	backend.DistLinuxRISCV64,
-}
-
-var DynamicDistributions = []backend.Distribution{
-	backend.DistDarwinAMD64,
-	backend.DistDarwinARM64,
-	backend.DistWindowsAMD64,
-	backend.DistWindowsARM64,
-	backend.DistLinuxAMD64Dynamic,
-	backend.DistLinuxAMD64DynamicMusl,
-}
-
+// DistroFlags returns a flag for every distribution in the backend.DistributionCapabilities table.
+// Distributions that aren't in the table have no flag, so artifact strings that use them don't set the distribution and are rejected.
 func DistroFlags() []pipeline.Flag {
-	// These distributions have specific options that set some stuff.
-	f := []pipeline.Flag{
-		{
-			Name: string(backend.DistLinuxARMv6),
-			Options: map[pipeline.FlagOption]any{
-				Distribution: string(backend.DistLinuxARMv6),
-				Static:       true,
-				RPI:          true,
-			},
-		},
-	}
-
-	for _, v := range StaticDistributions {
+	distros := backend.SupportedDistributions()
+	f := make([]pipeline.Flag, len(distros))
+	for i, v := range distros {
+		c, _ := backend.DistroCapabilities(v)
 		d := string(v)
-		f = append(f, pipeline.Flag{
+		f[i] = pipeline.Flag{
 			Name: d,
 			Options: map[pipeline.FlagOption]any{
 				Distribution: d,
-				Static:       true,
+				Static:       c.Static,
 			},
-		})
-	}
-	for _, v := range DynamicDistributions {
-		d := string(v)
-		f = append(f, pipeline.Flag{
-			Name: d,
-			Options: map[pipeline.FlagOption]any{
-				Distribution: d,
-				Static:       false,
-			},
-		})
+		}
+
+		// These distributions have specific options that set some stuff.
+		if v == backend.DistLinuxARMv6 {
+			f[i].Options[RPI] = true
+		}
 	}
 
 	return f
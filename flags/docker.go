@@ -1,17 +1,36 @@
 package flags
 
-import "github.com/grafana/grafana-build/pipeline"
+import (
+	"github.com/grafana/grafana-build/docker"
+	"github.com/grafana/grafana-build/pipeline"
+)
 
 var (
-	Ubuntu             pipeline.FlagOption = "docker-ubuntu"
+	// DockerBase is set by the 'base=<name>' flags to the name of a docker.Variant.
+	DockerBase         pipeline.FlagOption = "docker-base"
 	DockerRepositories pipeline.FlagOption = "docker-repos"
 )
 
-var DockerFlags = []pipeline.Flag{
-	{
-		Name: "ubuntu",
-		Options: map[pipeline.FlagOption]any{
-			Ubuntu: true,
-		},
+// DockerFlags has a 'base=<name>' flag for each docker.Variant.
+// 'ubuntu' is kept as an alias of 'base=ubuntu' so that existing artifact strings like 'docker:grafana:linux/amd64:ubuntu' still work.
+var DockerFlags = append(DockerBaseFlags(docker.Variants), pipeline.Flag{
+	Name: "ubuntu",
+	Options: map[pipeline.FlagOption]any{
+		DockerBase: docker.VariantUbuntu.Name,
 	},
+})
+
+// DockerBaseFlags returns a 'base=<name>' flag for each variant.
+func DockerBaseFlags(variants []docker.Variant) []pipeline.Flag {
+	f := make([]pipeline.Flag, len(variants))
+	for i, v := range variants {
+		f[i] = pipeline.Flag{
+			Name: "base=" + v.Name,
+			Options: map[pipeline.FlagOption]any{
+				DockerBase: v.Name,
+			},
+		}
+	}
+
+	return f
 }
@@ -18,6 +18,9 @@ const (
 	GoTags        pipeline.FlagOption = "go-tag"
 	GoExperiments pipeline.FlagOption = "go-experiments"
 	Sign          pipeline.FlagOption = "sign"
+	PGO           pipeline.FlagOption = "pgo"
+	Race          pipeline.FlagOption = "race"
+	Cover         pipeline.FlagOption = "cover"
 
 	// Pretty much only used to set the deb or RPM internal package name (and file name) to `{}-nightly` and/or `{}-rpi`
 	Nightly pipeline.FlagOption = "nightly"
@@ -80,6 +83,30 @@ var SignFlag = pipeline.Flag{
 	},
 }
 
+// PGOFlag optimizes the backend with the profile from the --pgo-profile argument.
+var PGOFlag = pipeline.Flag{
+	Name: "pgo",
+	Options: map[pipeline.FlagOption]any{
+		PGO: true,
+	},
+}
+
+// RaceFlag builds the backend with the race detector.
+var RaceFlag = pipeline.Flag{
+	Name: "race",
+	Options: map[pipeline.FlagOption]any{
+		Race: true,
+	},
+}
+
+// CoverFlag builds the backend with coverage counters.
+var CoverFlag = pipeline.Flag{
+	Name: "cover",
+	Options: map[pipeline.FlagOption]any{
+		Cover: true,
+	},
+}
+
 var NightlyFlag = pipeline.Flag{
 	Name: "nightly",
 	Options: map[pipeline.FlagOption]any{
@@ -75,7 +75,7 @@ func Build(builder *dagger.Container, opts BuildOpts, targz *dagger.File) *dagge
 
 	fpmArgs = append(fpmArgs, opts.ExtraArgs...)
 
-	if arch := backend.PackageArch(opts.Distribution); arch != "" {
+	if arch := backend.PackageArch(opts.Distribution, string(opts.PackageType)); arch != "" {
 		fpmArgs = append(fpmArgs, fmt.Sprintf("--architecture=%s", arch))
 	}
 
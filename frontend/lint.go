@@ -0,0 +1,90 @@
+package frontend
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// LintResultsDir is the directory in the builder where the results of the lint scripts are written.
+const LintResultsDir = "/tmp/frontend-lint"
+
+// DefaultLintScripts are the scripts in the package.json of Grafana that check the frontend without running it.
+var DefaultLintScripts = []string{"typecheck", "lint"}
+
+// LintFileName returns the name of the files of a script in the results, without the ':' of scripts like 'lint:ts'.
+func LintFileName(script string) string {
+	return strings.ReplaceAll(script, ":", "-")
+}
+
+// LintCommand returns the shell command that runs the package.json script and writes its log and exit code to the results directory.
+// The command always succeeds so that the other scripts run and the results can be exported when a script fails.
+func LintCommand(results string, script string) string {
+	name := LintFileName(script)
+
+	return fmt.Sprintf("mkdir -p %[1]s && yarn run %[2]s > %[3]s 2>&1; echo $? > %[4]s",
+		results,
+		script,
+		path.Join(results, name+".log"),
+		path.Join(results, name+".exit-code"),
+	)
+}
+
+// lintReportScript reads the log and the exit code of each script in the results directory, and writes a 'junit.xml' report with a test case for each script
+// and the exit code of the first script that failed. The log of a failed script is the text of its failure.
+// It runs in the builder so that the report is part of the results directory without reading the results first.
+const lintReportScript = `const fs = require('fs');
+const path = require('path');
+const [results, ...scripts] = process.argv.slice(1);
+const escape = (s) => String(s)
+  .replace(/[^\x09\x0A\x0D\x20-\uD7FF\uE000-\uFFFD\u{10000}-\u{10FFFF}]/gu, '\uFFFD')
+  .replace(/&/g, '&amp;')
+  .replace(/</g, '&lt;')
+  .replace(/>/g, '&gt;')
+  .replace(/"/g, '&quot;')
+  .replace(/'/g, '&#39;');
+let code = 0;
+let failures = 0;
+const cases = scripts.map((script) => {
+  const name = path.join(results, script.replace(/:/g, '-'));
+  const exitCode = parseInt(fs.readFileSync(name + '.exit-code', 'utf8'), 10);
+  if (isNaN(exitCode)) {
+    throw new Error("error reading the exit code of '" + script + "'");
+  }
+  if (exitCode === 0) {
+    return '  <testcase name="' + escape(script) + '" classname="frontend-lint"></testcase>';
+  }
+  failures++;
+  if (code === 0) {
+    code = exitCode;
+  }
+  const message = "'yarn run " + script + "' exited with code " + exitCode;
+  const log = fs.readFileSync(name + '.log', 'utf8');
+  return '  <testcase name="' + escape(script) + '" classname="frontend-lint">\n' +
+    '    <failure message="' + escape(message) + '">' + escape(log) + '</failure>\n' +
+    '  </testcase>';
+});
+fs.writeFileSync(path.join(results, 'junit.xml'), '<?xml version="1.0" encoding="UTF-8"?>\n' +
+  '<testsuite name="frontend-lint" tests="' + scripts.length + '" failures="' + failures + '">\n' +
+  cases.map((c) => c + '\n').join('') +
+  '</testsuite>\n');
+fs.writeFileSync(path.join(results, '` + ExitCodeFile + `'), code + '\n');`
+
+// LintReportCommand returns the command that writes the 'junit.xml' report and the exit code of the scripts to the results directory.
+func LintReportCommand(results string, scripts []string) []string {
+	return append([]string{"node", "-e", lintReportScript, results}, scripts...)
+}
+
+// Lint runs the package.json scripts in the builder one after the other and returns the results directory with their logs, a 'junit.xml' report, and the exit code of the first script that failed.
+func Lint(builder *dagger.Container, scripts []string) *dagger.Directory {
+	builder = builder.WithExec([]string{"mkdir", "-p", LintResultsDir})
+	for _, v := range scripts {
+		builder = builder.WithExec([]string{"/bin/sh", "-c", LintCommand(LintResultsDir, v)})
+	}
+
+	return builder.
+		WithExec(LintReportCommand(LintResultsDir, scripts)).
+		Directory(LintResultsDir)
+}
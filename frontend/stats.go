@@ -0,0 +1,224 @@
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"dagger.io/dagger"
+)
+
+const (
+	// StatsResultsDir is the directory in the builder where the webpack stats and the size reports are written.
+	StatsResultsDir = "/tmp/frontend-stats"
+
+	// statsInputDir is the directory in the builder where the baseline and the budgets are written, outside of the results directory.
+	statsInputDir = "/tmp/frontend-stats-input"
+
+	// OtherAssets is the name of the group of assets that aren't part of a chunk, like images and fonts.
+	OtherAssets = "(other assets)"
+)
+
+// StatsBuild runs 'yarn run build' in the builder and writes the webpack stats to 'stats.json' in the results directory.
+func StatsBuild(builder *dagger.Container) *dagger.Container {
+	return builder.
+		WithExec([]string{"mkdir", "-p", StatsResultsDir}).
+		WithExec([]string{"yarn", "run", "build", fmt.Sprintf("--json=%s", path.Join(StatsResultsDir, "stats.json"))})
+}
+
+// A SizeBudget limits the size of a chunk or of the whole frontend, in bytes. Zero is no limit.
+type SizeBudget struct {
+	Max int64 `json:"max,omitempty"`
+	// MaxIncrease is the growth that is allowed compared to the baseline.
+	MaxIncrease int64 `json:"maxIncrease,omitempty"`
+}
+
+// SizeBudgets are the budgets of the frontend. Chunks that aren't in Chunks use the Default budget.
+type SizeBudgets struct {
+	Total   SizeBudget            `json:"total"`
+	Default SizeBudget            `json:"default"`
+	Chunks  map[string]SizeBudget `json:"chunks,omitempty"`
+}
+
+// ReadSizeBudgets reads SizeBudgets from the JSON file at path.
+func ReadSizeBudgets(path string) (*SizeBudgets, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := &SizeBudgets{}
+	if err := json.Unmarshal(b, budgets); err != nil {
+		return nil, fmt.Errorf("error parsing size budgets '%s': %w", path, err)
+	}
+
+	return budgets, nil
+}
+
+// A Chunk is the assets of a webpack chunk.
+type Chunk struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+	Size  int64    `json:"size"`
+	// BaselineSize is the size of the chunk in the baseline, or 0 if it's a new chunk or there is no baseline.
+	BaselineSize int64 `json:"baselineSize,omitempty"`
+}
+
+// A StatsReport is the size of each chunk of the frontend. The report of a previous build is the baseline of the next one.
+type StatsReport struct {
+	Total         int64    `json:"total"`
+	BaselineTotal int64    `json:"baselineTotal,omitempty"`
+	Chunks        []Chunk  `json:"chunks"`
+	Violations    []string `json:"violations,omitempty"`
+}
+
+// ReadStatsReport reads a StatsReport that was exported by a previous build from the JSON file at path.
+func ReadStatsReport(path string) (*StatsReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatsReport{}
+	if err := json.Unmarshal(b, report); err != nil {
+		return nil, fmt.Errorf("error parsing frontend stats baseline '%s': %w", path, err)
+	}
+
+	return report, nil
+}
+
+// statsReportScript groups the assets of the webpack stats by chunk, compares them to the baseline, checks the budgets, and writes the report
+// as 'report.json' and 'report.md' to the results directory. The paths of the baseline and the budgets are empty if there are none.
+// Chunks without a name are named after their id, which is stable between builds with deterministic chunk ids, and are sorted by size, largest first.
+// It runs in the builder so that the reports are part of the results directory without reading the stats first.
+// formatSize is the same as stringutil.FormatSize.
+const statsReportScript = `const fs = require('fs');
+const path = require('path');
+const [stats, results, baselinePath, budgetsPath] = process.argv.slice(1);
+const read = (p) => (p ? JSON.parse(fs.readFileSync(p, 'utf8')) : null);
+const baseline = read(baselinePath);
+const budgets = read(budgetsPath);
+
+const formatSize = (b) => {
+  const unit = 1024;
+  let sign = '';
+  if (b < 0) {
+    sign = '-';
+    b = -b;
+  }
+  if (b < unit) {
+    return sign + b + ' B';
+  }
+  let div = unit;
+  let exp = 0;
+  for (let n = Math.floor(b / unit); n >= unit; n = Math.floor(n / unit)) {
+    div *= unit;
+    exp++;
+  }
+  return sign + (b / div).toFixed(1) + ' ' + 'KMGTPE'[exp] + 'iB';
+};
+const formatChange = (size, baseline) => {
+  const delta = size - baseline;
+  const percent = (delta / baseline) * 100;
+  return (delta >= 0 ? '+' : '') + formatSize(delta) + ' (' + (percent >= 0 ? '+' : '') + percent.toFixed(1) + '%)';
+};
+const checkBudget = (name, size, baseline, budget) => {
+  const violations = [];
+  if (budget && budget.max && size > budget.max) {
+    violations.push(name + ' is ' + formatSize(size) + ', which is more than its budget of ' + formatSize(budget.max));
+  }
+  if (budget && budget.maxIncrease && baseline && size - baseline > budget.maxIncrease) {
+    violations.push(name + ' grew ' + formatSize(size - baseline) + ', which is more than its budget of ' + formatSize(budget.maxIncrease));
+  }
+  return violations;
+};
+
+const chunks = new Map();
+let total = 0;
+for (const a of require(stats).assets || []) {
+  const names = a.chunkNames || [];
+  const ids = (a.chunks || []).map(String);
+  const name = names.length ? names[0] : ids.length ? 'chunk-' + ids[0] : '` + OtherAssets + `';
+  if (!chunks.has(name)) {
+    chunks.set(name, { name: name, files: [], size: 0 });
+  }
+  const c = chunks.get(name);
+  c.files.push(a.name);
+  c.size += a.size;
+  total += a.size;
+}
+
+const previous = new Map(((baseline && baseline.chunks) || []).map((c) => [c.name, c.size]));
+const report = { total: total };
+if (baseline && baseline.total) {
+  report.baselineTotal = baseline.total;
+}
+report.chunks = [...chunks.values()].map((c) => {
+  c.files.sort();
+  if (previous.get(c.name)) {
+    c.baselineSize = previous.get(c.name);
+  }
+  return c;
+});
+report.chunks.sort((a, b) => (a.size !== b.size ? b.size - a.size : a.name < b.name ? -1 : a.name > b.name ? 1 : 0));
+
+if (budgets) {
+  const violations = checkBudget('the frontend', report.total, report.baselineTotal, budgets.total);
+  for (const c of report.chunks) {
+    const budget = budgets.chunks && Object.prototype.hasOwnProperty.call(budgets.chunks, c.name) ? budgets.chunks[c.name] : budgets.default;
+    violations.push(...checkBudget("chunk '" + c.name + "'", c.size, c.baselineSize, budget));
+  }
+  if (violations.length) {
+    report.violations = violations;
+  }
+}
+
+let md = '## Frontend bundle size\n\nTotal: ' + formatSize(report.total);
+if (report.baselineTotal) {
+  md += ', ' + formatChange(report.total, report.baselineTotal) + ' compared to the baseline';
+}
+md += '\n\n';
+if (report.violations) {
+  md += '**Size budgets exceeded:**\n\n' + report.violations.map((v) => '- ' + v + '\n').join('') + '\n';
+}
+md += '| CHUNK | SIZE | BASELINE | CHANGE |\n| --- | --- | --- | --- |\n';
+for (const c of report.chunks) {
+  const baseline = c.baselineSize ? formatSize(c.baselineSize) : '-';
+  const change = c.baselineSize ? formatChange(c.size, c.baselineSize) : '-';
+  md += '| ' + c.name.replace(/\|/g, '\\|') + ' | ' + formatSize(c.size) + ' | ' + baseline + ' | ' + change + ' |\n';
+}
+
+fs.writeFileSync(path.join(results, 'report.json'), JSON.stringify(report, null, 2) + '\n');
+fs.writeFileSync(path.join(results, 'report.md'), md);`
+
+// StatsReportCommand returns the command that writes 'report.json' and 'report.md' for the webpack stats file to the results directory.
+// The paths of the baseline report and the budgets can be empty.
+func StatsReportCommand(stats, results, baseline, budgets string) []string {
+	return []string{"node", "-e", statsReportScript, stats, results, baseline, budgets}
+}
+
+// Stats runs 'yarn run build' in the builder and returns the results directory with the webpack stats and the size reports. The baseline and the budgets can be nil.
+func Stats(builder *dagger.Container, baseline *StatsReport, budgets *SizeBudgets) (*dagger.Directory, error) {
+	var baselinePath, budgetsPath string
+	if baseline != nil {
+		b, err := json.Marshal(baseline)
+		if err != nil {
+			return nil, err
+		}
+		baselinePath = path.Join(statsInputDir, "baseline.json")
+		builder = builder.WithNewFile(baselinePath, dagger.ContainerWithNewFileOpts{Contents: string(b)})
+	}
+	if budgets != nil {
+		b, err := json.Marshal(budgets)
+		if err != nil {
+			return nil, err
+		}
+		budgetsPath = path.Join(statsInputDir, "budgets.json")
+		builder = builder.WithNewFile(budgetsPath, dagger.ContainerWithNewFileOpts{Contents: string(b)})
+	}
+
+	return StatsBuild(builder).
+		WithExec(StatsReportCommand(path.Join(StatsResultsDir, "stats.json"), StatsResultsDir, baselinePath, budgetsPath)).
+		Directory(StatsResultsDir), nil
+}
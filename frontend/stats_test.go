@@ -0,0 +1,127 @@
+package frontend_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/frontend"
+)
+
+// stats are the assets of a webpack stats file. Chunk ids are numbers in webpack stats.
+const stats = `{"assets": [
+  {"name": "app.1a2b.js", "size": 3000, "chunkNames": ["app"], "chunks": ["app"]},
+  {"name": "app.1a2b.css", "size": 1000, "chunkNames": ["app"], "chunks": ["app"]},
+  {"name": "4321.9f8e.js", "size": 2000, "chunkNames": [], "chunks": [4321]},
+  {"name": "img/logo.3c4d.svg", "size": 500}
+]}`
+
+// statsReport runs the report command with the baseline and the budgets, which can be nil, and returns the report and the markdown.
+func statsReport(t *testing.T, baseline *frontend.StatsReport, budgets *frontend.SizeBudgets) (*frontend.StatsReport, string) {
+	t.Helper()
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+
+	dir := t.TempDir()
+	write := func(name string, v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	statsPath := write("stats.json", json.RawMessage(stats))
+	baselinePath, budgetsPath := "", ""
+	if baseline != nil {
+		baselinePath = write("baseline.json", baseline)
+	}
+	if budgets != nil {
+		budgetsPath = write("budgets.json", budgets)
+	}
+
+	cmd := frontend.StatsReportCommand(statsPath, dir, baselinePath, budgetsPath)
+	if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+
+	r, err := frontend.ReadStatsReport(filepath.Join(dir, "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r, string(md)
+}
+
+func TestStatsReport(t *testing.T) {
+	r, _ := statsReport(t, nil, nil)
+	if r.Total != 6500 {
+		t.Errorf("expected a total of 6500 but got %d", r.Total)
+	}
+
+	expect := []frontend.Chunk{
+		{Name: "app", Files: []string{"app.1a2b.css", "app.1a2b.js"}, Size: 4000},
+		{Name: "chunk-4321", Files: []string{"4321.9f8e.js"}, Size: 2000},
+		{Name: frontend.OtherAssets, Files: []string{"img/logo.3c4d.svg"}, Size: 500},
+	}
+	if !reflect.DeepEqual(r.Chunks, expect) {
+		t.Errorf("expected chunks %+v but got %+v", expect, r.Chunks)
+	}
+	if len(r.Violations) != 0 {
+		t.Errorf("expected no violations without budgets but got %v", r.Violations)
+	}
+}
+
+func TestStatsReportBudgets(t *testing.T) {
+	baseline := &frontend.StatsReport{
+		Total: 5000,
+		Chunks: []frontend.Chunk{
+			{Name: "app", Size: 3000},
+			{Name: "chunk-4321", Size: 1900},
+		},
+	}
+	budgets := &frontend.SizeBudgets{
+		Total:   frontend.SizeBudget{Max: 10000},
+		Default: frontend.SizeBudget{MaxIncrease: 200},
+		Chunks: map[string]frontend.SizeBudget{
+			"app": {Max: 3500, MaxIncrease: 2000},
+		},
+	}
+
+	r, md := statsReport(t, baseline, budgets)
+	if r.BaselineTotal != 5000 {
+		t.Errorf("expected a baseline total of 5000 but got %d", r.BaselineTotal)
+	}
+	if r.Chunks[0].BaselineSize != 3000 || r.Chunks[2].BaselineSize != 0 {
+		t.Errorf("expected the baseline sizes of the chunks but got %+v", r.Chunks)
+	}
+
+	// 'app' is over its max, 'chunk-4321' grew 100 B which is within the default budget, and the other assets are new.
+	if len(r.Violations) != 1 || !strings.Contains(r.Violations[0], "chunk 'app' is 3.9 KiB") {
+		t.Errorf("expected only the max of 'app' to be exceeded but got %v", r.Violations)
+	}
+
+	for _, v := range []string{
+		"Total: 6.3 KiB, +1.5 KiB (+30.0%) compared to the baseline",
+		"**Size budgets exceeded:**",
+		"| app | 3.9 KiB | 2.9 KiB | +1000 B (+33.3%) |",
+		"| (other assets) | 500 B | - | - |",
+	} {
+		if !strings.Contains(md, v) {
+			t.Errorf("expected '%s' in the markdown:\n%s", v, md)
+		}
+	}
+}
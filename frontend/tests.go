@@ -0,0 +1,52 @@
+package frontend
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/shards"
+)
+
+const (
+	// TestResultsDir is the directory in the builder where the results of the jest tests are written.
+	TestResultsDir = "/tmp/frontend-test"
+
+	// ExitCodeFile is the name of the file in the results that has the exit code of the tests, or of the first lint script that failed.
+	ExitCodeFile = "exit-code"
+)
+
+// JestCommand returns the shell command that runs the jest tests of the shard and writes 'junit.xml', the 'coverage' reports, 'test.log', and the exit code to the results directory.
+// The command always succeeds so that the results can be exported when tests fail.
+func JestCommand(results string, shard shards.Shard) string {
+	args := []string{"yarn", "jest",
+		"--ci",
+		"--reporters=default",
+		"--reporters=jest-junit",
+		"--coverage",
+		fmt.Sprintf("--coverageDirectory=%s", path.Join(results, "coverage")),
+		"--coverageReporters=lcov",
+		"--coverageReporters=cobertura",
+		"--coverageReporters=json-summary",
+	}
+
+	// '--shard' was added in jest 28, so it's only used when the tests are split.
+	if shard.Total > 1 {
+		args = append(args, fmt.Sprintf("--shard=%s", shard))
+	}
+
+	return fmt.Sprintf("mkdir -p %[1]s && JEST_JUNIT_OUTPUT_DIR=%[1]s JEST_JUNIT_OUTPUT_NAME=junit.xml %[2]s > %[3]s 2>&1; echo $? > %[4]s",
+		results,
+		strings.Join(args, " "),
+		path.Join(results, "test.log"),
+		path.Join(results, ExitCodeFile),
+	)
+}
+
+// Test runs the jest tests of the shard in the builder and returns the results directory.
+func Test(builder *dagger.Container, shard shards.Shard) *dagger.Directory {
+	return builder.
+		WithExec([]string{"/bin/sh", "-c", JestCommand(TestResultsDir, shard)}).
+		Directory(TestResultsDir)
+}
@@ -0,0 +1,103 @@
+package frontend_test
+
+import (
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/shards"
+)
+
+func TestJestCommand(t *testing.T) {
+	cmd := frontend.JestCommand("/tmp/results", shards.Shard{Index: 2, Total: 4})
+	for _, v := range []string{
+		"JEST_JUNIT_OUTPUT_DIR=/tmp/results ",
+		"--reporters=jest-junit",
+		"--coverageDirectory=/tmp/results/coverage",
+		"--shard=2/4",
+		"> /tmp/results/test.log 2>&1; echo $? > /tmp/results/exit-code",
+	} {
+		if !strings.Contains(cmd, v) {
+			t.Errorf("expected '%s' in '%s'", v, cmd)
+		}
+	}
+
+	if cmd := frontend.JestCommand("/tmp/results", shards.All); strings.Contains(cmd, "--shard") {
+		t.Errorf("expected no '--shard' without shards but got '%s'", cmd)
+	}
+}
+
+func TestLintCommand(t *testing.T) {
+	cmd := frontend.LintCommand("/tmp/results", "lint:ts")
+	expect := "mkdir -p /tmp/results && yarn run lint:ts > /tmp/results/lint-ts.log 2>&1; echo $? > /tmp/results/lint-ts.exit-code"
+	if cmd != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, cmd)
+	}
+}
+
+func TestLintReportCommand(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+
+	results := t.TempDir()
+	for name, content := range map[string]string{
+		"typecheck.exit-code": "0\n",
+		"typecheck.log":       "ok",
+		"lint.exit-code":      "1\n",
+		"lint.log":            "error  'foo' is defined but never used \x1b[31m<bar>\x1b[0m",
+		"lint-ts.exit-code":   "2\n",
+		"lint-ts.log":         "error",
+	} {
+		if err := os.WriteFile(filepath.Join(results, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := frontend.LintReportCommand(results, []string{"typecheck", "lint", "lint:ts"})
+	if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+
+	b, err := os.ReadFile(filepath.Join(results, "junit.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := struct {
+		Name      string `xml:"name,attr"`
+		Tests     int    `xml:"tests,attr"`
+		Failures  int    `xml:"failures,attr"`
+		TestCases []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+				Text    string `xml:",chardata"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	}{}
+	if err := xml.Unmarshal(b, &report); err != nil {
+		t.Fatalf("expected a valid JUnit report but got %s:\n%s", err, b)
+	}
+
+	if report.Name != "frontend-lint" || report.Tests != 3 || report.Failures != 2 || len(report.TestCases) != 3 {
+		t.Fatalf("expected 3 test cases with 2 failures but got:\n%s", b)
+	}
+	if report.TestCases[0].Failure != nil {
+		t.Errorf("expected 'typecheck' to pass but got:\n%s", b)
+	}
+	if f := report.TestCases[1].Failure; f == nil || f.Message != "'yarn run lint' exited with code 1" || f.Text != "error  'foo' is defined but never used \uFFFD[31m<bar>\uFFFD[0m" {
+		t.Errorf("expected the log of 'lint' in its failure but got:\n%s", b)
+	}
+
+	code, err := os.ReadFile(filepath.Join(results, frontend.ExitCodeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(code) != "1\n" {
+		t.Errorf("expected the exit code of the first script that failed but got '%s'", code)
+	}
+}
@@ -0,0 +1,176 @@
+package gcom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrorAlreadyExists = errors.New("already exists")
+
+// APIError is returned when grafana.com responds with an unexpected status code.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// Is returns true for ErrorAlreadyExists if grafana.com responded that the resource that was being created already exists.
+func (e *APIError) Is(target error) bool {
+	if target != ErrorAlreadyExists {
+		return false
+	}
+
+	return e.StatusCode == http.StatusConflict || strings.Contains(strings.ToLower(e.Body), "already exists")
+}
+
+// retryable returns true if the request could succeed if it's tried again.
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Client makes requests to the grafana.com API.
+type Client struct {
+	HTTPClient *http.Client
+	URL        *url.URL
+	ApiKey     string
+
+	// Attempts is the maximum number of times a request is sent when it fails with a network error, a 429, or a 5xx status.
+	Attempts int
+	// Backoff is the time to wait before the first retry. It is doubled after every attempt, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// DryRun, if true, writes every request that would modify grafana.com to Out instead of sending it.
+	DryRun bool
+	Out    io.Writer
+}
+
+// NewClient creates a Client from the GCOMOpts with the default retry settings.
+func NewClient(opts *GCOMOpts) *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Minute,
+		},
+		URL:        opts.URL,
+		ApiKey:     opts.ApiKey,
+		Attempts:   5,
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+		DryRun:     opts.DryRun,
+		Out:        os.Stderr,
+	}
+}
+
+// Do sends the request with the JSON encoded body to the path relative to the client URL, and decodes the response into out.
+// out can be nil if the response body should be ignored.
+func (c *Client) Do(ctx context.Context, method string, path []string, body any, out any) error {
+	u := c.URL.JoinPath(path...)
+
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = b
+	}
+
+	if c.DryRun && method != http.MethodGet {
+		fmt.Fprintf(c.Out, "[dry-run] %s %s %s\n", method, u.String(), string(payload))
+		return nil
+	}
+
+	var (
+		backoff = c.Backoff
+		err     error
+	)
+
+	for attempt := 1; ; attempt++ {
+		err = c.do(ctx, method, u, payload, out)
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.retryable() {
+			return err
+		}
+
+		if attempt >= c.Attempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if c.MaxBackoff != 0 && backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method string, u *url.URL, payload []byte, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.ApiKey)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &APIError{
+			Method:     method,
+			URL:        u.String(),
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(b)),
+		}
+	}
+
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("error decoding response from %s %s: %w", method, u.String(), err)
+	}
+
+	return nil
+}
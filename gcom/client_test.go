@@ -0,0 +1,177 @@
+package gcom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/grafana/grafana-build/gcom"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *gcom.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL + "/api/grafana")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gcom.NewClient(&gcom.GCOMOpts{
+		URL:    u,
+		ApiKey: "api-key",
+	})
+	client.Backoff = 0
+
+	return client
+}
+
+func TestClientPublishVersion(t *testing.T) {
+	payload := &gcom.GCOMVersionPayload{
+		Version:     "10.2.0",
+		Stable:      true,
+		WhatsNewURL: "https://grafana.com/docs/grafana/next/whatsnew/whats-new-in-v10-2/",
+	}
+
+	t.Run("created", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/grafana/versions" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer api-key" {
+				t.Errorf("unexpected authorization header '%s'", auth)
+			}
+
+			body := &gcom.GCOMVersionPayload{}
+			if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+				t.Fatal(err)
+			}
+			if *body != *payload {
+				t.Errorf("expected payload %+v but got %+v", payload, body)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(body)
+		})
+
+		version, err := client.PublishVersion(context.Background(), payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version.AlreadyExists {
+			t.Errorf("expected version to be created")
+		}
+		if version.Version != "10.2.0" {
+			t.Errorf("expected version '10.2.0' but got '%s'", version.Version)
+		}
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		for _, status := range []int{http.StatusConflict, http.StatusBadRequest} {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"code":"InvalidArgument","message":"Version 10.2.0 already exists"}`))
+			})
+
+			version, err := client.PublishVersion(context.Background(), payload)
+			if err != nil {
+				t.Fatalf("expected no error for status %d but got %s", status, err)
+			}
+			if !version.AlreadyExists {
+				t.Errorf("expected version to already exist for status %d", status)
+			}
+		}
+	})
+}
+
+func TestClientPublishPackage(t *testing.T) {
+	payload := &gcom.GCOMPackagePayload{
+		OS:     "linux",
+		URL:    "https://dl.grafana.com/oss/release/grafana-10.2.0.linux-amd64.tar.gz",
+		Sha256: "abc",
+		Arch:   "amd64",
+	}
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/grafana/versions/10.2.0/packages" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{}`))
+		})
+
+		if _, err := client.PublishPackage(context.Background(), "10.2.0", payload); err != nil {
+			t.Fatal(err)
+		}
+		if n := requests.Load(); n != 3 {
+			t.Errorf("expected 3 requests but got %d", n)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		client.Attempts = 2
+
+		_, err := client.PublishPackage(context.Background(), "10.2.0", payload)
+		apiErr := &gcom.APIError{}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected a 503 APIError but got %v", err)
+		}
+		if n := requests.Load(); n != 2 {
+			t.Errorf("expected 2 requests but got %d", n)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"invalid api key"}`))
+		})
+
+		_, err := client.PublishPackage(context.Background(), "10.2.0", payload)
+		if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+			t.Fatalf("expected an authorization error but got %v", err)
+		}
+		if errors.Is(err, gcom.ErrorAlreadyExists) {
+			t.Errorf("expected error not to be ErrorAlreadyExists")
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("expected 1 request but got %d", n)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request in dry run: %s %s", r.Method, r.URL.Path)
+		})
+		out := &bytes.Buffer{}
+		client.DryRun = true
+		client.Out = out
+
+		if _, err := client.PublishPackage(context.Background(), "10.2.0", payload); err != nil {
+			t.Fatal(err)
+		}
+		if s := out.String(); !strings.Contains(s, "POST") || !strings.Contains(s, `"sha256":"abc"`) {
+			t.Errorf("expected dry run to print the request but got '%s'", s)
+		}
+	})
+}
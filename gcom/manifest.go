@@ -0,0 +1,54 @@
+package gcom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ManifestPackage is the metadata of a single package in a release manifest.
+type ManifestPackage struct {
+	// Name is the file name of the package, like 'grafana_10.2.0_abcd123_linux_amd64.tar.gz'.
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	PackageInfo
+	// Sha256 is the checksum of the package. If it is empty, then the checksum is computed from the package.
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// A Manifest lists the packages of a release along with their metadata, so that the grafana.com payloads don't have to be guessed from file names.
+type Manifest struct {
+	Packages []ManifestPackage `json:"packages"`
+}
+
+// Package returns the metadata of the package with the file name of path, or nil if the manifest does not have it.
+func (m *Manifest) Package(path string) *ManifestPackage {
+	if m == nil {
+		return nil
+	}
+
+	name := filepath.Base(path)
+	for i, v := range m.Packages {
+		if v.Name == name {
+			return &m.Packages[i]
+		}
+	}
+
+	return nil
+}
+
+// ReadManifest reads a JSON release manifest from the file at path.
+func ReadManifest(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest '%s': %w", path, err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,117 @@
+package gcom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrorNoPackageMapping = errors.New("no grafana.com package mapping matches the package")
+
+// PackageInfo describes a package that is published to grafana.com, independently of its file name.
+type PackageInfo struct {
+	// Ext is the extension of the package, without a leading '.', like 'tar.gz', 'deb', or 'exe'.
+	Ext string `json:"ext"`
+	// OS is the Go operating system of the package, like 'linux' or 'windows'.
+	OS string `json:"os"`
+	// Arch is the architecture of the package as it is used in distributions, like 'amd64' or 'arm/v7'.
+	Arch string `json:"arch"`
+}
+
+// A PackageMapping maps the extension, OS, and architecture of a package to the 'os' and 'arch' values used by grafana.com.
+// Empty match fields match any value. The first mapping that matches a package is used.
+type PackageMapping struct {
+	Ext  string `json:"ext"`
+	OS   string `json:"os,omitempty"`
+	Arch string `json:"arch,omitempty"`
+
+	// GCOMOS is the 'os' value that grafana.com uses for the package.
+	GCOMOS string `json:"gcomOS"`
+	// GCOMArch is the 'arch' value that grafana.com uses for the package. If it is empty, then the architecture of the package
+	// is used without slashes, so 'arm/v7' becomes 'armv7'.
+	GCOMArch string `json:"gcomArch,omitempty"`
+}
+
+func (m PackageMapping) Matches(p PackageInfo) bool {
+	return m.Ext == p.Ext &&
+		(m.OS == "" || m.OS == p.OS) &&
+		(m.Arch == "" || m.Arch == p.Arch)
+}
+
+type PackageMappings []PackageMapping
+
+// DefaultPackageMappings are the mappings for every package type that is published to grafana.com.
+var DefaultPackageMappings = PackageMappings{
+	{Ext: "deb", OS: "linux", GCOMOS: "deb"},
+	{Ext: "rpm", OS: "linux", GCOMOS: "rhel"},
+	{Ext: "tar.gz", OS: "linux", GCOMOS: "linux"},
+	{Ext: "tar.gz", OS: "darwin", GCOMOS: "darwin"},
+	{Ext: "zip", OS: "darwin", GCOMOS: "darwin"},
+	{Ext: "tar.gz", OS: "windows", GCOMOS: "win"},
+	{Ext: "zip", OS: "windows", GCOMOS: "win"},
+	{Ext: "exe", OS: "windows", GCOMOS: "win-installer"},
+	{Ext: "msi", OS: "windows", GCOMOS: "win-installer"},
+}
+
+// Map returns the grafana.com 'os' and 'arch' of the package using the first mapping that matches it.
+// If no mapping matches, then an error wrapping ErrorNoPackageMapping is returned.
+func (m PackageMappings) Map(p PackageInfo) (string, string, error) {
+	for _, v := range m {
+		if !v.Matches(p) {
+			continue
+		}
+
+		arch := v.GCOMArch
+		if arch == "" {
+			arch = strings.ReplaceAll(p.Arch, "/", "")
+		}
+
+		return v.GCOMOS, arch, nil
+	}
+
+	return "", "", fmt.Errorf("%w: ext '%s', os '%s', arch '%s'", ErrorNoPackageMapping, p.Ext, p.OS, p.Arch)
+}
+
+// Ext returns the extension of the file name that matches the longest extension in the mappings.
+// If none match, then the text after the last '.' is returned.
+func (m PackageMappings) Ext(name string) string {
+	ext := ""
+	for _, v := range m {
+		if len(v.Ext) > len(ext) && strings.HasSuffix(name, "."+v.Ext) {
+			ext = v.Ext
+		}
+	}
+	if ext != "" {
+		return ext
+	}
+
+	if i := strings.LastIndex(name, "."); i != -1 {
+		return name[i+1:]
+	}
+
+	return ""
+}
+
+// ReadPackageMappings reads a JSON list of package mappings from the file at path.
+// The mappings in the file are used before the DefaultPackageMappings, so they can override them.
+func ReadPackageMappings(path string) (PackageMappings, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := PackageMappings{}
+	if err := json.Unmarshal(b, &mappings); err != nil {
+		return nil, fmt.Errorf("error parsing package mappings in '%s': %w", path, err)
+	}
+
+	for i, v := range mappings {
+		if v.Ext == "" || v.GCOMOS == "" {
+			return nil, fmt.Errorf("package mapping %d in '%s': 'ext' and 'gcomOS' are required", i, path)
+		}
+	}
+
+	return append(mappings, DefaultPackageMappings...), nil
+}
@@ -0,0 +1,88 @@
+package gcom_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana-build/gcom"
+)
+
+func TestPackageMappingsMap(t *testing.T) {
+	cases := map[string]struct {
+		info gcom.PackageInfo
+		os   string
+		arch string
+	}{
+		"linux tar.gz": {info: gcom.PackageInfo{Ext: "tar.gz", OS: "linux", Arch: "arm/v7"}, os: "linux", arch: "armv7"},
+		"deb":          {info: gcom.PackageInfo{Ext: "deb", OS: "linux", Arch: "arm64"}, os: "deb", arch: "arm64"},
+		"rpm":          {info: gcom.PackageInfo{Ext: "rpm", OS: "linux", Arch: "amd64"}, os: "rhel", arch: "amd64"},
+		"windows zip":  {info: gcom.PackageInfo{Ext: "zip", OS: "windows", Arch: "amd64"}, os: "win", arch: "amd64"},
+		"windows exe":  {info: gcom.PackageInfo{Ext: "exe", OS: "windows", Arch: "amd64"}, os: "win-installer", arch: "amd64"},
+		"arm64 msi":    {info: gcom.PackageInfo{Ext: "msi", OS: "windows", Arch: "arm64"}, os: "win-installer", arch: "arm64"},
+		"darwin zip":   {info: gcom.PackageInfo{Ext: "zip", OS: "darwin", Arch: "arm64"}, os: "darwin", arch: "arm64"},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			os, arch, err := gcom.DefaultPackageMappings.Map(v.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if os != v.os || arch != v.arch {
+				t.Errorf("expected '%s/%s' but got '%s/%s'", v.os, v.arch, os, arch)
+			}
+		})
+	}
+
+	t.Run("unmatched", func(t *testing.T) {
+		_, _, err := gcom.DefaultPackageMappings.Map(gcom.PackageInfo{Ext: "docker.tar.gz", OS: "linux", Arch: "amd64"})
+		if !errors.Is(err, gcom.ErrorNoPackageMapping) {
+			t.Errorf("expected ErrorNoPackageMapping but got %v", err)
+		}
+	})
+}
+
+func TestPackageMappingsExt(t *testing.T) {
+	names := map[string]string{
+		"grafana_10.2.0_abc_linux_amd64.tar.gz":          "tar.gz",
+		"grafana_10.2.0_abc_linux_amd64.docker.tar.gz":   "tar.gz",
+		"grafana_10.2.0_abc_windows_amd64.exe":           "exe",
+		"grafana_10.2.0_abc_linux_amd64.deb":             "deb",
+		"grafana_10.2.0_abc_linux_amd64.unknown":         "unknown",
+		"grafana-enterprise_10.2.0_abc_darwin_arm64.zip": "zip",
+	}
+
+	for k, v := range names {
+		if ext := gcom.DefaultPackageMappings.Ext(k); ext != v {
+			t.Errorf("expected ext of '%s' to be '%s' but got '%s'", k, v, ext)
+		}
+	}
+}
+
+func TestReadPackageMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.json")
+	if err := os.WriteFile(path, []byte(`[{"ext": "tar.gz", "os": "linux", "arch": "arm/v6", "gcomOS": "linux", "gcomArch": "armv6l"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings, err := gcom.ReadPackageMappings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, arch, _ := mappings.Map(gcom.PackageInfo{Ext: "tar.gz", OS: "linux", Arch: "arm/v6"}); arch != "armv6l" {
+		t.Errorf("expected the mapping in the file to be used but got arch '%s'", arch)
+	}
+	if os, _, _ := mappings.Map(gcom.PackageInfo{Ext: "rpm", OS: "linux", Arch: "amd64"}); os != "rhel" {
+		t.Errorf("expected the default mappings to be used but got os '%s'", os)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"ext": "tar.gz"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gcom.ReadPackageMappings(path); err == nil {
+		t.Errorf("expected an error for a mapping without 'gcomOS'")
+	}
+}
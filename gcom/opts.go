@@ -13,6 +13,18 @@ type GCOMOpts struct {
 	ApiKey      string
 	Beta        bool
 	Nightly     bool
+
+	// DryRun prints the requests that would modify grafana.com instead of sending them.
+	DryRun bool
+
+	// WhatsNewURL and ReleaseNotesURL are templates for the URLs of a published version. See URLTemplateValues for the available values.
+	WhatsNewURL     string
+	ReleaseNotesURL string
+
+	// Mappings are used to find the grafana.com 'os' and 'arch' of a package.
+	Mappings PackageMappings
+	// Manifest, if set, provides the metadata of the published packages.
+	Manifest *Manifest
 }
 
 func GCOMOptsFromFlags(c cliutil.CLIContext) (*GCOMOpts, error) {
@@ -24,11 +36,35 @@ func GCOMOptsFromFlags(c cliutil.CLIContext) (*GCOMOpts, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	mappings := DefaultPackageMappings
+	if path := c.String("package-mappings"); path != "" {
+		m, err := ReadPackageMappings(path)
+		if err != nil {
+			return nil, err
+		}
+		mappings = m
+	}
+
+	var manifest *Manifest
+	if path := c.String("manifest"); path != "" {
+		m, err := ReadManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		manifest = m
+	}
+
 	return &GCOMOpts{
-		URL:         apiUrl,
-		DownloadURL: downloadUrl,
-		ApiKey:      c.String("api-key"),
-		Beta:        c.Bool("beta"),
-		Nightly:     c.Bool("nightly"),
+		URL:             apiUrl,
+		DownloadURL:     downloadUrl,
+		ApiKey:          c.String("api-key"),
+		Beta:            c.Bool("beta"),
+		Nightly:         c.Bool("nightly"),
+		DryRun:          c.Bool("dry-run"),
+		WhatsNewURL:     c.String("whats-new-url"),
+		ReleaseNotesURL: c.String("release-notes-url"),
+		Mappings:        mappings,
+		Manifest:        manifest,
 	}, nil
 }
@@ -2,10 +2,8 @@ package gcom
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-
-	"dagger.io/dagger"
+	"errors"
+	"net/http"
 )
 
 type GCOMVersionPayload struct {
@@ -25,36 +23,50 @@ type GCOMPackagePayload struct {
 	Arch   string `json:"arch"`
 }
 
-// PublishGCOMVersion publishes a version to grafana.com.
-func PublishGCOMVersion(ctx context.Context, d *dagger.Client, versionPayload *GCOMVersionPayload, opts *GCOMOpts) (string, error) {
-	versionApiUrl := opts.URL.JoinPath("/versions")
+// GCOMVersion is a version returned by grafana.com.
+type GCOMVersion struct {
+	GCOMVersionPayload
+	// AlreadyExists is true if the version was published before, in which case it was not modified.
+	AlreadyExists bool `json:"-"`
+}
+
+// GCOMPackage is a package returned by grafana.com.
+type GCOMPackage struct {
+	GCOMPackagePayload
+	// AlreadyExists is true if the package was published before, in which case it was not modified.
+	AlreadyExists bool `json:"-"`
+}
 
-	jsonVersionPayload, err := json.Marshal(versionPayload)
-	if err != nil {
-		return "", err
+// PublishVersion publishes a version to grafana.com. If the version already exists, then it is not modified and no error is returned.
+func (c *Client) PublishVersion(ctx context.Context, payload *GCOMVersionPayload) (*GCOMVersion, error) {
+	version := &GCOMVersion{
+		GCOMVersionPayload: *payload,
 	}
 
-	apiKeySecret := d.SetSecret("gcom-api-key", opts.ApiKey)
+	if err := c.Do(ctx, http.MethodPost, []string{"versions"}, payload, version); err != nil {
+		if errors.Is(err, ErrorAlreadyExists) {
+			version.AlreadyExists = true
+			return version, nil
+		}
+		return nil, err
+	}
 
-	return d.Container().From("alpine/curl").
-		WithSecretVariable("GCOM_API_KEY", apiKeySecret).
-		WithExec([]string{"/bin/sh", "-c", fmt.Sprintf(`curl -H "Content-Type: application/json" -H "Authorization: Bearer $GCOM_API_KEY" -d '%s' %s`, string(jsonVersionPayload), versionApiUrl.String())}).
-		Stdout(ctx)
+	return version, nil
 }
 
-// PublishGCOMPackage publishes a package to grafana.com.
-func PublishGCOMPackage(ctx context.Context, d *dagger.Client, packagePayload *GCOMPackagePayload, opts *GCOMOpts, version string) (string, error) {
-	packagesApiUrl := opts.URL.JoinPath("/versions/", version, "/packages")
-
-	jsonPackagePayload, err := json.Marshal(packagePayload)
-	if err != nil {
-		return "", err
+// PublishPackage publishes a package of an already published version to grafana.com. If the package already exists, then it is not modified and no error is returned.
+func (c *Client) PublishPackage(ctx context.Context, version string, payload *GCOMPackagePayload) (*GCOMPackage, error) {
+	pkg := &GCOMPackage{
+		GCOMPackagePayload: *payload,
 	}
 
-	apiKeySecret := d.SetSecret("gcom-api-key", opts.ApiKey)
+	if err := c.Do(ctx, http.MethodPost, []string{"versions", version, "packages"}, payload, pkg); err != nil {
+		if errors.Is(err, ErrorAlreadyExists) {
+			pkg.AlreadyExists = true
+			return pkg, nil
+		}
+		return nil, err
+	}
 
-	return d.Container().From("alpine/curl").
-		WithSecretVariable("GCOM_API_KEY", apiKeySecret).
-		WithExec([]string{"/bin/sh", "-c", fmt.Sprintf(`curl -H "Content-Type: application/json" -H "Authorization: Bearer $GCOM_API_KEY" -d '%s' %s`, string(jsonPackagePayload), packagesApiUrl.String())}).
-		Stdout(ctx)
+	return pkg, nil
 }
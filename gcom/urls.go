@@ -0,0 +1,42 @@
+package gcom
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultWhatsNewURLFormat     = "https://grafana.com/docs/grafana/next/whatsnew/whats-new-in-v{{ .major }}-{{ .minor }}/"
+	DefaultReleaseNotesURLFormat = "https://grafana.com/docs/grafana/next/release-notes/"
+)
+
+// URLTemplateValues returns the values that can be used in the 'whats-new-url' and 'release-notes-url' templates.
+func URLTemplateValues(version string) map[string]string {
+	version = strings.TrimPrefix(version, "v")
+	base, _, _ := strings.Cut(version, "-")
+	semver := append(strings.SplitN(base, ".", 3), "", "", "")
+
+	return map[string]string{
+		"version":      version,
+		"version_base": base,
+		"major":        semver[0],
+		"minor":        semver[1],
+		"patch":        semver[2],
+	}
+}
+
+// VersionURL renders the URL template format with the values for version.
+func VersionURL(format, version string) (string, error) {
+	tmpl, err := template.New("url").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tmpl.Execute(buf, URLTemplateValues(version)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
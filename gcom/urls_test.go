@@ -0,0 +1,42 @@
+package gcom_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-build/gcom"
+)
+
+func TestVersionURL(t *testing.T) {
+	cases := map[string]struct {
+		format  string
+		version string
+		expect  string
+	}{
+		"default whats new": {
+			format:  gcom.DefaultWhatsNewURLFormat,
+			version: "v10.2.1",
+			expect:  "https://grafana.com/docs/grafana/next/whatsnew/whats-new-in-v10-2/",
+		},
+		"prerelease": {
+			format:  "https://grafana.com/docs/grafana/v{{ .major }}.{{ .minor }}/release-notes/{{ .version_base }}/",
+			version: "10.3.0-beta1",
+			expect:  "https://grafana.com/docs/grafana/v10.3/release-notes/10.3.0/",
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			u, err := gcom.VersionURL(v.format, v.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u != v.expect {
+				t.Errorf("expected '%s' but got '%s'", v.expect, u)
+			}
+		})
+	}
+
+	if _, err := gcom.VersionURL("{{ .unknown }}", "10.2.0"); err == nil {
+		t.Errorf("expected an error for an unknown value")
+	}
+}
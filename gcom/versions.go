@@ -0,0 +1,76 @@
+package gcom
+
+import (
+	"context"
+	"net/http"
+)
+
+type versionList struct {
+	Items []GCOMVersion `json:"items"`
+}
+
+type packageList struct {
+	Items []GCOMPackage `json:"items"`
+}
+
+// ListVersions returns the versions that have been published to grafana.com.
+func (c *Client) ListVersions(ctx context.Context) ([]GCOMVersion, error) {
+	list := &versionList{}
+	if err := c.Do(ctx, http.MethodGet, []string{"versions"}, nil, list); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// GetVersion returns a single version from grafana.com.
+func (c *Client) GetVersion(ctx context.Context, version string) (*GCOMVersion, error) {
+	v := &GCOMVersion{}
+	if err := c.Do(ctx, http.MethodGet, []string{"versions", version}, nil, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ListPackages returns the packages that have been published for the version.
+func (c *Client) ListPackages(ctx context.Context, version string) ([]GCOMPackage, error) {
+	list := &packageList{}
+	if err := c.Do(ctx, http.MethodGet, []string{"versions", version, "packages"}, nil, list); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// UpdateVersion replaces the fields of an existing version with the ones in the payload.
+func (c *Client) UpdateVersion(ctx context.Context, payload *GCOMVersionPayload) (*GCOMVersion, error) {
+	version := &GCOMVersion{
+		GCOMVersionPayload: *payload,
+	}
+
+	if err := c.Do(ctx, http.MethodPost, []string{"versions", payload.Version}, payload, version); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// DeleteVersion deletes the version and all of its packages from grafana.com.
+func (c *Client) DeleteVersion(ctx context.Context, version string) error {
+	return c.Do(ctx, http.MethodDelete, []string{"versions", version}, nil, nil)
+}
+
+// DeletePackage deletes a single package of a version from grafana.com. os and arch match the values in the GCOMPackagePayload.
+func (c *Client) DeletePackage(ctx context.Context, version, os, arch string) error {
+	return c.Do(ctx, http.MethodDelete, []string{"versions", version, "packages", arch, os}, nil, nil)
+}
+
+// Promote marks a beta or nightly version as stable.
+func Promote(v GCOMVersionPayload) *GCOMVersionPayload {
+	v.Stable = true
+	v.Beta = false
+	v.Nightly = false
+
+	return &v
+}
@@ -0,0 +1,100 @@
+package gcom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-build/gcom"
+)
+
+func TestClientListVersions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/grafana/versions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"items":[{"version":"10.2.0","stable":true},{"version":"10.3.0-beta1","beta":true}]}`))
+	})
+
+	versions, err := client.ListVersions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions but got %d", len(versions))
+	}
+	if versions[1].Version != "10.3.0-beta1" || !versions[1].Beta {
+		t.Errorf("unexpected version %+v", versions[1])
+	}
+}
+
+func TestClientUpdateVersion(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/grafana/versions/10.3.0-beta1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		body := &gcom.GCOMVersionPayload{}
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Fatal(err)
+		}
+		if !body.Stable || body.Beta {
+			t.Errorf("expected a promoted payload but got %+v", body)
+		}
+		json.NewEncoder(w).Encode(body)
+	})
+
+	v, err := client.UpdateVersion(context.Background(), gcom.Promote(gcom.GCOMVersionPayload{
+		Version: "10.3.0-beta1",
+		Beta:    true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Stable {
+		t.Errorf("expected version to be stable but got %+v", v)
+	}
+}
+
+func TestClientDelete(t *testing.T) {
+	cases := map[string]struct {
+		delete func(c *gcom.Client) error
+		expect string
+	}{
+		"version": {
+			delete: func(c *gcom.Client) error { return c.DeleteVersion(context.Background(), "10.2.0") },
+			expect: "/api/grafana/versions/10.2.0",
+		},
+		"package": {
+			delete: func(c *gcom.Client) error {
+				return c.DeletePackage(context.Background(), "10.2.0", "deb", "arm64")
+			},
+			expect: "/api/grafana/versions/10.2.0/packages/arm64/deb",
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != v.expect {
+					t.Errorf("expected DELETE %s but got %s %s", v.expect, r.Method, r.URL.Path)
+				}
+			})
+			if err := v.delete(client); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestPromote(t *testing.T) {
+	v := gcom.GCOMVersionPayload{Version: "10.3.0-nightly", Nightly: true}
+	p := gcom.Promote(v)
+	if !p.Stable || p.Nightly || p.Beta {
+		t.Errorf("expected only stable to be set but got %+v", p)
+	}
+	if !v.Nightly {
+		t.Errorf("expected the original payload to be unchanged")
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -100,7 +100,7 @@ func CloneContainer(d *dagger.Client, opts *GitCloneOptions) (*dagger.Container,
 	checkoutArgs = append(checkoutArgs, "else exit 3; fi")
 
 	container = container.WithExec([]string{"/bin/sh", "-c", strings.Join(checkoutArgs, " ")})
-	log.Println(strings.Join(checkoutArgs, " "))
+	slog.Debug("Checking out", "args", strings.Join(checkoutArgs, " "))
 	return container, nil
 }
 
@@ -1,11 +1,21 @@
 package golang
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"dagger.io/dagger"
 )
 
+const (
+	// ModuleCacheDir is where the module cache volume is mounted. It's also set as GOMODCACHE, because the viceroy image doesn't use /go as its GOPATH.
+	ModuleCacheDir = "/go/pkg/mod"
+
+	// BuildCacheDir is where the GOCACHE volume is mounted.
+	BuildCacheDir = "/root/.cache/go-build"
+)
+
 func DownloadURL(version, arch string) string {
 	return fmt.Sprintf("https://go.dev/dl/go%s.linux-%s.tar.gz", version, arch)
 }
@@ -21,9 +31,22 @@ func Container(d *dagger.Client, platform dagger.Platform, version string) *dagg
 }
 
 func WithCachedGoDependencies(container *dagger.Container, dir *dagger.Directory, cache *dagger.CacheVolume) *dagger.Container {
-	return container.WithMountedCache("/go/pkg/mod", cache, dagger.ContainerWithMountedCacheOpts{
+	return container.WithMountedCache(ModuleCacheDir, cache, dagger.ContainerWithMountedCacheOpts{
 		Source: dir,
-	})
+	}).WithEnvVariable("GOMODCACHE", ModuleCacheDir)
+}
+
+// WithBuildCache mounts the cache volume as the Go build cache (GOCACHE), so that packages that didn't change aren't compiled again.
+func WithBuildCache(container *dagger.Container, cache *dagger.CacheVolume) *dagger.Container {
+	return container.WithMountedCache(BuildCacheDir, cache).
+		WithEnvVariable("GOCACHE", BuildCacheDir)
+}
+
+// ModuleCacheKey returns the name of the module cache volume for the contents of a go.sum file.
+// Versions of Grafana with the same dependencies share the volume.
+func ModuleCacheKey(goSum string) string {
+	sum := sha256.Sum256([]byte(goSum))
+	return "go-mod-" + hex.EncodeToString(sum[:])[:16]
 }
 
 func ModuleDir(d *dagger.Client, platform dagger.Platform, src *dagger.Directory, goVersion string) *dagger.Directory {
@@ -0,0 +1,21 @@
+package golang_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/golang"
+)
+
+func TestModuleCacheKey(t *testing.T) {
+	a := golang.ModuleCacheKey("github.com/a/b v1.0.0 h1:abc=\n")
+	if a != golang.ModuleCacheKey("github.com/a/b v1.0.0 h1:abc=\n") {
+		t.Error("expected the same go.sum to have the same key")
+	}
+	if a == golang.ModuleCacheKey("github.com/a/b v1.0.1 h1:def=\n") {
+		t.Error("expected a different go.sum to have a different key")
+	}
+	if !strings.HasPrefix(a, "go-mod-") || len(a) != len("go-mod-")+16 {
+		t.Errorf("unexpected key '%s'", a)
+	}
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats are the values allowed in the '--log-format' flag.
+var Formats = []string{FormatText, FormatJSON}
+
+// Level is the level of the loggers created with New. Commands set it to slog.LevelDebug when the '--verbose' flag is set.
+var Level = &slog.LevelVar{}
+
+// New creates a logger that writes to w in the given format.
+func New(w io.Writer, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{
+		Level: Level,
+	}
+
+	switch format {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+
+	return nil, fmt.Errorf("unrecognized log format '%s'", format)
+}
+
+// SetVerbose sets the Level to debug if verbose is true.
+func SetVerbose(verbose bool) {
+	if verbose {
+		Level.Set(slog.LevelDebug)
+	}
+}
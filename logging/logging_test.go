@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/grafana/grafana-build/logging"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log, err := logging.New(buf, logging.FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		log.Info("Done exporting artifact", "artifact", "targz:linux/amd64")
+
+		line := map[string]any{}
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatalf("expected a JSON line but got '%s'", buf.String())
+		}
+		if line["msg"] != "Done exporting artifact" || line["artifact"] != "targz:linux/amd64" {
+			t.Errorf("unexpected log line %v", line)
+		}
+	})
+
+	t.Run("verbose", func(t *testing.T) {
+		defer logging.Level.Set(slog.LevelInfo)
+
+		buf := &bytes.Buffer{}
+		log, err := logging.New(buf, logging.FormatText)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		log.Debug("hidden")
+		logging.SetVerbose(true)
+		log.Debug("shown")
+
+		if bytes.Contains(buf.Bytes(), []byte("hidden")) || !bytes.Contains(buf.Bytes(), []byte("shown")) {
+			t.Errorf("expected only the debug log after SetVerbose but got '%s'", buf.String())
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, err := logging.New(&bytes.Buffer{}, "xml"); err == nil {
+			t.Errorf("expected an error for an unknown format")
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileClient is an otlptrace.Client that writes spans to a file instead of sending them to a collector.
+// Every batch of spans is written as an OTLP JSON 'ExportTraceServiceRequest' on its own line, which is the same format that the
+// OpenTelemetry Collector's file exporter uses.
+type FileClient struct {
+	Path string
+	// Overwrite replaces the file if it exists. Without it, Start fails with an error that wraps os.ErrExist so that the trace of an earlier run isn't lost.
+	Overwrite bool
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+var _ otlptrace.Client = &FileClient{}
+
+func NewFileClient(path string) *FileClient {
+	return &FileClient{
+		Path: path,
+	}
+}
+
+func (c *FileClient) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if c.Overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(c.Path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating trace file: %w", err)
+	}
+
+	c.file = f
+	return nil
+}
+
+func (c *FileClient) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+func (c *FileClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	b, err := protojson.Marshal(&collectortracepb.ExportTraceServiceRequest{
+		ResourceSpans: spans,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return fmt.Errorf("trace file '%s' is not open", c.Path)
+	}
+
+	_, err = c.file.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,53 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana-build/otel"
+)
+
+func TestFileClientStart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := os.WriteFile(path, []byte("earlier run\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("existing file", func(t *testing.T) {
+		c := otel.NewFileClient(path)
+		if err := c.Start(ctx); !errors.Is(err, os.ErrExist) {
+			t.Fatalf("expected an error that wraps os.ErrExist but got %v", err)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "earlier run\n" {
+			t.Errorf("expected the trace of the earlier run to be kept but got '%s'", b)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		c := otel.NewFileClient(path)
+		c.Overwrite = true
+		if err := c.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Stop(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 0 {
+			t.Errorf("expected the trace file to be replaced but got '%s'", b)
+		}
+	})
+}
@@ -2,7 +2,8 @@ package otel
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -23,7 +25,7 @@ func createExporterClient(ctx context.Context) otlptrace.Client {
 	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 
 	if endpoint == "" {
-		log.Print("OTEL_EXPORTER_OTLP_ENDPOINT not set. Disabling tracing.")
+		slog.Debug("OTEL_EXPORTER_OTLP_ENDPOINT not set. Disabling the OTLP exporters.")
 		return nil
 	}
 	if protocol == "grpc" {
@@ -35,18 +37,26 @@ func createExporterClient(ctx context.Context) otlptrace.Client {
 	return otlptracegrpc.NewClient()
 }
 
-func Setup(ctx context.Context) func(context.Context) error {
-	client := createExporterClient(ctx)
-	if client == nil {
+// Setup configures the global tracer and meter providers to export to OTEL_EXPORTER_OTLP_ENDPOINT.
+// If traceFile is not empty, then spans are also written to that file, even if no endpoint is set. An existing trace file is only replaced if overwrite is true.
+// The returned function flushes and shuts down the providers.
+func Setup(ctx context.Context, traceFile string, overwrite bool) func(context.Context) error {
+	clients := []otlptrace.Client{}
+	remote := createExporterClient(ctx)
+	if remote != nil {
+		clients = append(clients, remote)
+	}
+	if traceFile != "" {
+		client := NewFileClient(traceFile)
+		client.Overwrite = overwrite
+		clients = append(clients, client)
+	}
+	if len(clients) == 0 {
 		return func(ctx context.Context) error {
 			return nil
 		}
 	}
-	exporter, err := otlptrace.New(ctx, client)
 
-	if err != nil {
-		log.Fatal(err)
-	}
 	res, err := resource.Merge(
 		resource.Default(),
 		resource.NewWithAttributes(
@@ -55,15 +65,42 @@ func Setup(ctx context.Context) func(context.Context) error {
 		),
 	)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
-	otel.SetTextMapPropagator(propagation.TraceContext{})
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+
+	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
-	)
+	}
+	for _, client := range clients {
+		exporter, err := otlptrace.New(ctx, client)
+		if err != nil {
+			fatal(err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp := sdktrace.NewTracerProvider(opts...)
 	otel.SetTracerProvider(tp)
-	return tp.Shutdown
+
+	if remote == nil {
+		return tp.Shutdown
+	}
+
+	mp, err := setupMetrics(ctx, res)
+	if err != nil {
+		fatal(err)
+	}
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}
+}
+
+// fatal logs the error and exits. Setup can't return errors without breaking the commands that don't use tracing.
+func fatal(err error) {
+	slog.Error("Failed to set up OpenTelemetry", "error", err)
+	os.Exit(1)
 }
 
 func FindParentTrace(ctx context.Context) context.Context {
@@ -71,7 +108,7 @@ func FindParentTrace(ctx context.Context) context.Context {
 	if traceParent == "" {
 		return ctx
 	}
-	log.Printf("Parent trace found: %s", traceParent)
+	slog.Info("Parent trace found", "traceparent", traceParent)
 	carrier := make(propagation.MapCarrier)
 	carrier.Set("traceparent", traceParent)
 	prop := otel.GetTextMapPropagator()
@@ -89,6 +126,12 @@ func Tracer(name string) trace.Tracer {
 	return otel.Tracer(name)
 }
 
+// Meter is a simple wrapper around otel.Meter in order to abstract that
+// package.
+func Meter(name string) metric.Meter {
+	return otel.Meter(name)
+}
+
 func RecordFailed(span trace.Span, err error, msg string) {
 	span.RecordError(err)
 	span.SetStatus(codes.Error, msg)
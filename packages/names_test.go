@@ -91,6 +91,25 @@ func TestFileName(t *testing.T) {
 			t.Errorf("name '%s' does not match expected name '%s'", name, expected)
 		}
 	})
+	t.Run("It should use the os and arch of BSD distros", func(t *testing.T) {
+		distro := backend.DistFreeBSDARM64
+		opts := packages.NameOpts{
+			Name:      "grafana",
+			Version:   "v1.0.1-test",
+			BuildID:   "333",
+			Distro:    distro,
+			Extension: "tar.gz",
+		}
+
+		expected := "grafana_v1.0.1-test_333_freebsd_arm64.tar.gz"
+		name, _ := packages.FileName(opts.Name, opts.Version, opts.BuildID, opts.Distro, opts.Extension)
+		if name != expected {
+			t.Errorf("name '%s' does not match expected name '%s'", name, expected)
+		}
+		if got := packages.NameOptsFromFileName(name); got.Distro != distro {
+			t.Errorf("distro '%s' from the name does not match '%s'", got.Distro, distro)
+		}
+	})
 	t.Run("It should support grafana names with multiple hyphens", func(t *testing.T) {
 		distro := backend.Distribution("plan9/arm/v6")
 		opts := packages.NameOpts{
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 
 	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/scan"
 )
 
 var (
@@ -59,6 +60,35 @@ type ArtifactHandler interface {
 	VerifyDirectory(context.Context, *dagger.Client, *dagger.Directory) error
 }
 
+// An ArtifactScanner is an ArtifactHandler that can be scanned for vulnerabilities when the '--scan' flag is provided.
+// Implementing this interface is optional; artifacts that don't implement it are not scanned.
+type ArtifactScanner interface {
+	ScanFile(ctx context.Context, client *dagger.Client, file *dagger.File, opts *scan.Opts) (*scan.Report, error)
+}
+
+// ArtifactCanScan returns true if the handler of the artifact is an ArtifactScanner.
+// Wrappers like ArtifactHandlerLogger implement ScanFile for every handler, so they report whether the handler they wrap can be scanned with 'CanScan'.
+func ArtifactCanScan(a *Artifact) bool {
+	if w, ok := a.Handler.(interface{ CanScan() bool }); ok {
+		return w.CanScan()
+	}
+
+	_, ok := a.Handler.(ArtifactScanner)
+	return ok
+}
+
+// An ArtifactVerifier is an ArtifactHandler that is verified even without the '--verify' flag, like test artifacts that fail when the tests fail.
+// Implementing this interface is optional; artifacts that don't implement it are only verified with '--verify'.
+type ArtifactVerifier interface {
+	AlwaysVerify() bool
+}
+
+// ArtifactAlwaysVerify returns true if the handler of the artifact is an ArtifactVerifier that is always verified.
+func ArtifactAlwaysVerify(a *Artifact) bool {
+	v, ok := a.Handler.(ArtifactVerifier)
+	return ok && v.AlwaysVerify()
+}
+
 type Artifact struct {
 	// ArtifactString is the artifact string provided by the user.
 	// If the artifact is being initialized as a dependency where an artifact string is not provided,
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/scan"
 )
 
 type ArtifactHandlerLogger struct {
@@ -111,6 +112,51 @@ func (a *ArtifactHandlerLogger) VerifyDirectory(ctx context.Context, client *dag
 	return nil
 }
 
+// AlwaysVerify returns true if the underlying handler is an ArtifactVerifier that is always verified.
+func (a *ArtifactHandlerLogger) AlwaysVerify() bool {
+	v, ok := a.Handler.(ArtifactVerifier)
+	return ok && v.AlwaysVerify()
+}
+
+// CanScan returns true if the underlying handler is an ArtifactScanner.
+func (a *ArtifactHandlerLogger) CanScan() bool {
+	_, ok := a.Handler.(ArtifactScanner)
+	return ok
+}
+
+// ScanFile scans the file if the underlying handler is an ArtifactScanner. If it is not, then a nil report is returned.
+func (a *ArtifactHandlerLogger) ScanFile(ctx context.Context, client *dagger.Client, file *dagger.File, opts *scan.Opts) (*scan.Report, error) {
+	s, ok := a.Handler.(ArtifactScanner)
+	if !ok {
+		a.log.DebugContext(ctx, "artifact does not support scanning")
+		return nil, nil
+	}
+
+	a.log.InfoContext(ctx, "scanning file...")
+	report, err := s.ScanFile(ctx, client, file, opts)
+	if err != nil {
+		a.log.InfoContext(ctx, "error scanning file", "error", err)
+		return nil, err
+	}
+	if report == nil {
+		a.log.InfoContext(ctx, "file was not scanned")
+		return nil, nil
+	}
+	a.log.InfoContext(ctx, "done scanning file", "vulnerabilities", len(report.Vulnerabilities))
+
+	return report, nil
+}
+
+// Resources returns the resources of the action if the underlying handler is an ArtifactResourcer. If it is not, then nil is returned.
+func (a *ArtifactHandlerLogger) Resources(ctx context.Context, action string) []Resource {
+	r, ok := a.Handler.(ArtifactResourcer)
+	if !ok {
+		return nil
+	}
+
+	return r.Resources(ctx, action)
+}
+
 func ArtifactWithLogging(ctx context.Context, log *slog.Logger, a *Artifact) (*Artifact, error) {
 	h := a.Handler
 	f, err := a.Handler.Filename(ctx)
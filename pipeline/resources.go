@@ -0,0 +1,51 @@
+package pipeline
+
+import "context"
+
+// A ResourceClass groups actions that compete for the same resource on the runner, like CPU or memory.
+// Each class can be limited separately with the '--parallel-<class>' flags.
+type ResourceClass string
+
+const (
+	// ResourceBackend is used by CPU-heavy actions, like compiling the Go backend.
+	ResourceBackend ResourceClass = "backend"
+	// ResourceFrontend is used by memory-heavy actions, like building the frontend with webpack or running e2e tests in a browser.
+	ResourceFrontend ResourceClass = "frontend"
+	// ResourceNetwork is used by actions that mostly upload or download files, like publishing or downloading a vulnerability database.
+	ResourceNetwork ResourceClass = "network"
+)
+
+// ResourceClasses is the list of every ResourceClass.
+var ResourceClasses = []ResourceClass{
+	ResourceBackend,
+	ResourceFrontend,
+	ResourceNetwork,
+}
+
+// A Resource is the amount of a ResourceClass that an action uses while it runs.
+// An action with a Weight of 2 takes the place of 2 actions with a Weight of 1.
+type Resource struct {
+	Class  ResourceClass
+	Weight int64
+}
+
+// An ArtifactResourcer is an ArtifactHandler that declares the resources its actions ('export', 'verify', 'scan') use.
+// Implementing this interface is optional; actions of artifacts that don't implement it are only limited by '--parallel'.
+type ArtifactResourcer interface {
+	Resources(ctx context.Context, action string) []Resource
+}
+
+// ParallelFlag returns the name of the flag that limits the resource class, like 'parallel-frontend'.
+func ParallelFlag(class ResourceClass) string {
+	return "parallel-" + string(class)
+}
+
+// ArtifactResources returns the resources that the action on the artifact uses, or nil if its handler is not an ArtifactResourcer.
+func ArtifactResources(ctx context.Context, a *Artifact, action string) []Resource {
+	r, ok := a.Handler.(ArtifactResourcer)
+	if !ok {
+		return nil
+	}
+
+	return r.Resources(ctx, action)
+}
@@ -3,21 +3,19 @@ package pipelines
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
 	"github.com/grafana/grafana-build/docker"
+	"github.com/grafana/grafana-build/retry"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
 
 func ImageManifest(tag string) string {
-	log.Println(tag)
-	log.Println(tag)
-	log.Println(tag)
-	log.Println(tag)
+	slog.Debug("Finding image manifest", "tag", tag)
 	manifest := strings.ReplaceAll(tag, "-image-tags", "")
 	lastDash := strings.LastIndex(manifest, "-")
 	return manifest[:lastDash]
@@ -38,7 +36,7 @@ func LatestManifest(tag string) string {
 // Grafana's Dockerfile should support supplying a tar.gz using a --build-arg.
 func PublishDocker(ctx context.Context, d *dagger.Client, args PipelineArgs) error {
 	opts := args.DockerOpts
-	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts)
+	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts, args.S3Opts)
 	if err != nil {
 		return err
 	}
@@ -62,7 +60,7 @@ func PublishDocker(ctx context.Context, d *dagger.Client, args PipelineArgs) err
 		if err != nil {
 			return err
 		}
-		log.Println(tags)
+		slog.Debug("Publishing manifest tags", "tags", tags)
 		for _, tag := range tags {
 			// For each tag we publish an image and add the tag to the list of tags for a specific manifest
 			// Since each package has a maximum of 2 tags, this for loop will only run twice on a worst case scenario
@@ -74,7 +72,7 @@ func PublishDocker(ctx context.Context, d *dagger.Client, args PipelineArgs) err
 				manifestTags[manifest] = append(manifestTags[manifest], tag)
 			}
 
-			wg.Go(PublishPackageImageFunc(ctx, sm, d, packages[i], tag, opts))
+			wg.Go(PublishPackageImageFunc(ctx, sm, d, packages[i], tag, opts, args.RetryPolicies.For(retry.PhasePublish)))
 		}
 	}
 
@@ -85,50 +83,62 @@ func PublishDocker(ctx context.Context, d *dagger.Client, args PipelineArgs) err
 
 	for manifest, tags := range manifestTags {
 		// Publish each manifest
-		wg.Go(PublishDockerManifestFunc(ctx, sm, d, manifest, tags, opts))
+		wg.Go(PublishDockerManifestFunc(ctx, sm, d, manifest, tags, opts, args.RetryPolicies.For(retry.PhasePublish)))
 	}
 
 	return wg.Wait()
 }
 
-func PublishPackageImageFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, pkg *dagger.File, tag string, opts *docker.DockerOpts) func() error {
+func PublishPackageImageFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, pkg *dagger.File, tag string, opts *docker.DockerOpts, policy retry.Policy) func() error {
 	return func() error {
-		log.Printf("[%s] Attempting to publish image", tag)
-		log.Printf("[%s] Acquiring semaphore", tag)
+		slog.Info("Attempting to publish image", "tag", tag)
+		slog.Info("Acquiring semaphore", "tag", tag)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", tag)
+		slog.Info("Acquired semaphore", "tag", tag)
 
-		log.Printf("[%s] Publishing image", tag)
-		out, err := docker.PublishPackageImage(ctx, d, pkg, tag, opts.Username, opts.Password, opts.Registry)
+		slog.Info("Publishing image", "tag", tag)
+		var out string
+		err := policy.Do(ctx, slog.With("tag", tag), func(ctx context.Context) error {
+			o, err := docker.PublishPackageImage(ctx, d, pkg, tag, opts.Username, opts.Password, opts.Registry)
+			out = o
+			return err
+		})
+		emitPublishEvent("", tag, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", tag, err)
 		}
-		log.Printf("[%s] Done publishing image", tag)
+		slog.Info("Done publishing image", "tag", tag)
 
 		fmt.Fprintln(Stdout, out)
 		return nil
 	}
 }
 
-func PublishDockerManifestFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, manifest string, tags []string, opts *docker.DockerOpts) func() error {
+func PublishDockerManifestFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, manifest string, tags []string, opts *docker.DockerOpts, policy retry.Policy) func() error {
 	return func() error {
-		log.Printf("[%s] Attempting to publish manifest", manifest)
-		log.Printf("[%s] Acquiring semaphore", manifest)
+		slog.Info("Attempting to publish manifest", "manifest", manifest)
+		slog.Info("Acquiring semaphore", "manifest", manifest)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", manifest)
+		slog.Info("Acquired semaphore", "manifest", manifest)
 
-		log.Printf("[%s] Publishing manifest", manifest)
-		out, err := docker.PublishManifest(ctx, d, manifest, tags, opts.Username, opts.Password, opts.Registry)
+		slog.Info("Publishing manifest", "manifest", manifest)
+		var out string
+		err := policy.Do(ctx, slog.With("manifest", manifest), func(ctx context.Context) error {
+			o, err := docker.PublishManifest(ctx, d, manifest, tags, opts.Username, opts.Password, opts.Registry)
+			out = o
+			return err
+		})
+		emitPublishEvent("", manifest, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", manifest, err)
 		}
-		log.Printf("[%s] Done publishing manifest", manifest)
+		slog.Info("Done publishing manifest", "manifest", manifest)
 
 		fmt.Fprintln(Stdout, out)
 		return nil
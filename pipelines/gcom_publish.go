@@ -2,10 +2,10 @@ package pipelines
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"dagger.io/dagger"
@@ -16,13 +16,12 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
-func VersionPayloadFromFileName(name string, opts *gcom.GCOMOpts) *gcom.GCOMVersionPayload {
+// VersionPayload returns the payload used to publish the version to grafana.com.
+func VersionPayload(version string, opts *gcom.GCOMOpts) (*gcom.GCOMVersionPayload, error) {
 	var (
-		tarOpts      = TarOptsFromFileName(name)
-		splitVersion = strings.Split(tarOpts.Version, ".")
-		stable       = true
-		nightly      = false
-		beta         = false
+		stable  = true
+		nightly = false
+		beta    = false
 	)
 
 	if opts.Beta {
@@ -35,45 +34,69 @@ func VersionPayloadFromFileName(name string, opts *gcom.GCOMOpts) *gcom.GCOMVers
 		nightly = true
 	}
 
+	whatsNew, err := gcom.VersionURL(opts.WhatsNewURL, version)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering whats-new-url: %w", err)
+	}
+	releaseNotes, err := gcom.VersionURL(opts.ReleaseNotesURL, version)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering release-notes-url: %w", err)
+	}
+
 	return &gcom.GCOMVersionPayload{
-		Version:         tarOpts.Version,
+		Version:         version,
 		ReleaseDate:     time.Now().Format(time.RFC3339Nano),
 		Stable:          stable,
 		Beta:            beta,
 		Nightly:         nightly,
-		WhatsNewURL:     fmt.Sprintf("https://grafana.com/docs/grafana/next/whatsnew/whats-new-in-v%s-%s/", splitVersion[0], splitVersion[1]),
-		ReleaseNotesURL: "https://grafana.com/docs/grafana/next/release-notes/",
-	}
+		WhatsNewURL:     whatsNew,
+		ReleaseNotesURL: releaseNotes,
+	}, nil
 }
 
-func PackagePayloadFromFile(ctx context.Context, d *dagger.Client, name string, file *dagger.File, opts *gcom.GCOMOpts) (*gcom.GCOMPackagePayload, error) {
-	tarOpts := TarOptsFromFileName(name)
-	ext := filepath.Ext(name)
-	os, _ := backend.OSAndArch(tarOpts.Distro)
-	arch := strings.ReplaceAll(backend.FullArch(tarOpts.Distro), "/", "")
-
-	if os == "windows" {
-		os = "win"
+// PackageMetadata returns the metadata of the package at path from the release manifest in the opts.
+// If the manifest is not set or does not have the package, then the metadata is read from the package's file name.
+func PackageMetadata(path string, opts *gcom.GCOMOpts) gcom.ManifestPackage {
+	if p := opts.Manifest.Package(path); p != nil {
+		return *p
 	}
 
-	if ext == ".deb" {
-		os = "deb"
-	}
-	if ext == ".rpm" {
-		os = "rhel"
-	}
-	if ext == ".exe" {
-		os = "win-installer"
+	var (
+		name    = filepath.Base(path)
+		tarOpts = TarOptsFromFileName(name)
+		os, _   = backend.OSAndArch(tarOpts.Distro)
+	)
+
+	return gcom.ManifestPackage{
+		Name:    name,
+		Version: tarOpts.Version,
+		PackageInfo: gcom.PackageInfo{
+			Ext:  opts.Mappings.Ext(name),
+			OS:   os,
+			Arch: backend.FullArch(tarOpts.Distro),
+		},
 	}
+}
 
-	sha256, err := containers.Sha256(d, file).Contents(ctx)
+func PackagePayloadFromFile(ctx context.Context, d *dagger.Client, name string, file *dagger.File, opts *gcom.GCOMOpts) (*gcom.GCOMPackagePayload, error) {
+	metadata := PackageMetadata(name, opts)
+	os, arch, err := opts.Mappings.Map(metadata.PackageInfo)
 	if err != nil {
 		return nil, err
 	}
 
+	sha256 := metadata.Sha256
+	if sha256 == "" {
+		s, err := containers.Sha256(d, file).Contents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sha256 = s
+	}
+
 	return &gcom.GCOMPackagePayload{
 		OS:     os,
-		URL:    opts.DownloadURL.JoinPath(name).String(),
+		URL:    opts.DownloadURL.JoinPath(metadata.Name).String(),
 		Sha256: sha256,
 		Arch:   arch,
 	}, nil
@@ -81,70 +104,86 @@ func PackagePayloadFromFile(ctx context.Context, d *dagger.Client, name string,
 
 func PublishGCOM(ctx context.Context, d *dagger.Client, args PipelineArgs) error {
 	var (
-		opts = args.GCOMOpts
-		wg   = &errgroup.Group{}
-		sm   = semaphore.NewWeighted(args.ConcurrencyOpts.Parallel)
+		opts   = args.GCOMOpts
+		client = gcom.NewClient(opts)
+		wg     = &errgroup.Group{}
+		sm     = semaphore.NewWeighted(args.ConcurrencyOpts.Parallel)
 	)
 
-	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts)
+	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts, args.S3Opts)
 	if err != nil {
 		return err
 	}
 
-	// Extract the package versions
+	// Extract the package versions and reject packages that can't be mapped before publishing anything
 	versionPayloads := make(map[string]*gcom.GCOMVersionPayload)
 	for _, name := range args.PackageInputOpts.Packages {
-		tarOpts := TarOptsFromFileName(name)
-		if _, ok := versionPayloads[tarOpts.Version]; !ok {
-			log.Printf("[%s] Building version payload", tarOpts.Version)
-			versionPayloads[tarOpts.Version] = VersionPayloadFromFileName(name, opts)
+		metadata := PackageMetadata(name, opts)
+		if _, _, err := opts.Mappings.Map(metadata.PackageInfo); err != nil {
+			return fmt.Errorf("[%s] error: %w", filepath.Base(name), err)
+		}
+		if _, ok := versionPayloads[metadata.Version]; !ok {
+			slog.Info("Building version payload", "version", metadata.Version)
+			payload, err := VersionPayload(metadata.Version, opts)
+			if err != nil {
+				return err
+			}
+			versionPayloads[metadata.Version] = payload
 		}
 	}
 
 	// Publish each version only once
 	for _, p := range versionPayloads {
-		log.Printf("[%s] Attempting to publish version", p.Version)
-		out, err := gcom.PublishGCOMVersion(ctx, d, p, opts)
+		slog.Info("Attempting to publish version", "version", p.Version)
+		version, err := client.PublishVersion(ctx, p)
 		if err != nil {
 			return err
 		}
-		log.Printf("[%s] Done publishing version", p.Version)
-		fmt.Fprintln(Stdout, strings.ReplaceAll(out, "\n", ""))
+		if version.AlreadyExists {
+			slog.Info("Version already exists", "version", p.Version)
+		}
+		slog.Info("Done publishing version", "version", p.Version)
+		if err := json.NewEncoder(Stdout).Encode(version); err != nil {
+			return err
+		}
 	}
 
 	// Publish the package(s)
 	for i, name := range args.PackageInputOpts.Packages {
-		wg.Go(PublishGCOMPackageFunc(ctx, sm, d, opts, name, packages[i]))
+		wg.Go(PublishGCOMPackageFunc(ctx, sm, d, client, opts, name, packages[i]))
 	}
 	return wg.Wait()
 }
 
-func PublishGCOMPackageFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, opts *gcom.GCOMOpts, path string, file *dagger.File) func() error {
+func PublishGCOMPackageFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, client *gcom.Client, opts *gcom.GCOMOpts, path string, file *dagger.File) func() error {
 	return func() error {
 		name := filepath.Base(path)
-		tarOpts := TarOptsFromFileName(name)
-		log.Printf("[%s] Attempting to publish package", name)
-		log.Printf("[%s] Acquiring semaphore", name)
+		metadata := PackageMetadata(path, opts)
+		slog.Info("Attempting to publish package", "package", name)
+		slog.Info("Acquiring semaphore", "package", name)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", name)
+		slog.Info("Acquired semaphore", "package", name)
 
-		log.Printf("[%s] Building package payload", name)
-		packagePayload, err := PackagePayloadFromFile(ctx, d, name, file, opts)
+		slog.Info("Building package payload", "package", name)
+		packagePayload, err := PackagePayloadFromFile(ctx, d, path, file, opts)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", name, err)
 		}
 
-		log.Printf("[%s] Publishing package", name)
-		out, err := gcom.PublishGCOMPackage(ctx, d, packagePayload, opts, tarOpts.Version)
+		slog.Info("Publishing package", "package", name)
+		pkg, err := client.PublishPackage(ctx, metadata.Version, packagePayload)
+		emitPublishEvent(name, packagePayload.URL, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", name, err)
 		}
-		log.Printf("[%s] Done publishing package", name)
+		if pkg.AlreadyExists {
+			slog.Info("Package already exists", "package", name)
+		}
+		slog.Info("Done publishing package", "package", name)
 
-		fmt.Fprintln(Stdout, strings.ReplaceAll(out, "\n", ""))
-		return nil
+		return json.NewEncoder(Stdout).Encode(pkg)
 	}
 }
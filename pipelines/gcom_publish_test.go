@@ -0,0 +1,61 @@
+package pipelines_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-build/gcom"
+	"github.com/grafana/grafana-build/pipelines"
+)
+
+func TestPackageMetadata(t *testing.T) {
+	opts := &gcom.GCOMOpts{
+		Mappings: gcom.DefaultPackageMappings,
+	}
+
+	t.Run("file name", func(t *testing.T) {
+		m := pipelines.PackageMetadata("dist/grafana-enterprise_10.2.0_abc123_linux_arm-7.deb", opts)
+		expect := gcom.PackageInfo{Ext: "deb", OS: "linux", Arch: "arm/v7"}
+		if m.Version != "10.2.0" || m.PackageInfo != expect {
+			t.Errorf("expected version '10.2.0' and %+v but got '%s' and %+v", expect, m.Version, m.PackageInfo)
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		opts := *opts
+		opts.Manifest = &gcom.Manifest{
+			Packages: []gcom.ManifestPackage{{
+				Name:        "grafana.zip",
+				Version:     "10.2.0",
+				PackageInfo: gcom.PackageInfo{Ext: "zip", OS: "darwin", Arch: "arm64"},
+				Sha256:      "abcd",
+			}},
+		}
+
+		m := pipelines.PackageMetadata("gs://bucket/grafana.zip", &opts)
+		if m.Version != "10.2.0" || m.OS != "darwin" || m.Sha256 != "abcd" {
+			t.Errorf("expected the manifest metadata to be used but got %+v", m)
+		}
+	})
+}
+
+func TestVersionPayload(t *testing.T) {
+	opts := &gcom.GCOMOpts{
+		Beta:            true,
+		WhatsNewURL:     gcom.DefaultWhatsNewURLFormat,
+		ReleaseNotesURL: "https://example.com/{{ .version }}",
+	}
+
+	p, err := pipelines.VersionPayload("10.3.0-beta1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Stable || !p.Beta {
+		t.Errorf("expected a beta version but got %+v", p)
+	}
+	if p.WhatsNewURL != "https://grafana.com/docs/grafana/next/whatsnew/whats-new-in-v10-3/" {
+		t.Errorf("unexpected whats new URL '%s'", p.WhatsNewURL)
+	}
+	if p.ReleaseNotesURL != "https://example.com/10.3.0-beta1" {
+		t.Errorf("unexpected release notes URL '%s'", p.ReleaseNotesURL)
+	}
+}
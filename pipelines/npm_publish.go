@@ -3,11 +3,13 @@ package pipelines
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"path/filepath"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
 	"github.com/grafana/grafana-build/frontend"
+	"github.com/grafana/grafana-build/retry"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
@@ -18,7 +20,7 @@ func PublishNPM(ctx context.Context, d *dagger.Client, args PipelineArgs) error
 		sm = semaphore.NewWeighted(args.ConcurrencyOpts.Parallel)
 	)
 
-	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts)
+	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts, args.S3Opts)
 	if err != nil {
 		return err
 	}
@@ -38,28 +40,34 @@ func PublishNPM(ctx context.Context, d *dagger.Client, args PipelineArgs) error
 		}
 
 		for _, path := range entries {
-			wg.Go(PublishNPMFunc(ctx, sm, d, artifacts.File(path), path, args.NpmToken, args.NpmRegistry, args.NpmTags))
+			wg.Go(PublishNPMFunc(ctx, sm, d, artifacts.File(path), path, args.NpmToken, args.NpmRegistry, args.NpmTags, args.RetryPolicies.For(retry.PhasePublish)))
 		}
 	}
 	return wg.Wait()
 }
 
-func PublishNPMFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, pkg *dagger.File, path, token, registry string, tags []string) func() error {
+func PublishNPMFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, pkg *dagger.File, path, token, registry string, tags []string, policy retry.Policy) func() error {
 	return func() error {
-		log.Printf("[%s] Attempting to publish package", path)
-		log.Printf("[%s] Acquiring semaphore", path)
+		slog.Info("Attempting to publish package", "package", path)
+		slog.Info("Acquiring semaphore", "package", path)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", path)
+		slog.Info("Acquired semaphore", "package", path)
 
-		log.Printf("[%s] Publishing package", path)
-		out, err := frontend.PublishNPM(ctx, d, pkg, token, registry, tags)
+		slog.Info("Publishing package", "package", path)
+		var out string
+		err := policy.Do(ctx, slog.With("package", path), func(ctx context.Context) error {
+			o, err := frontend.PublishNPM(ctx, d, pkg, token, registry, tags)
+			out = o
+			return err
+		})
+		emitPublishEvent(filepath.Base(path), registry, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", path, err)
 		}
-		log.Printf("[%s] Done publishing package", path)
+		slog.Info("Done publishing package", "package", path)
 
 		fmt.Fprintln(Stdout, out)
 		return nil
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/grafana/grafana-build/backend"
+	"github.com/grafana/grafana-build/docker"
 	"github.com/grafana/grafana-build/packages"
 )
 
@@ -36,8 +37,7 @@ func WithoutExt(name string) string {
 
 	// Explicitly handle `.gz` which might will also probably have a `.tar` extension as well.
 	if ext == ".gz" {
-		n = strings.TrimSuffix(n, ".ubuntu.docker.tar")
-		n = strings.TrimSuffix(n, ".docker.tar")
+		n = strings.TrimSuffix(n, "."+strings.TrimSuffix(docker.VariantFromFileName(name).Ext, ".gz"))
 		n = strings.TrimSuffix(n, ".tar")
 	}
 
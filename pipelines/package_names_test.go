@@ -16,6 +16,7 @@ func TestWithoutExt(t *testing.T) {
 		"grafana-enterprise_v1.0.1-test_333_plan9_arm-6.deb":                  "grafana-enterprise_v1.0.1-test_333_plan9_arm-6",
 		"grafana-enterprise_v1.0.1-test_333_plan9_arm-6.docker.tar.gz":        "grafana-enterprise_v1.0.1-test_333_plan9_arm-6",
 		"grafana-enterprise_v1.0.1-test_333_plan9_arm-6.ubuntu.docker.tar.gz": "grafana-enterprise_v1.0.1-test_333_plan9_arm-6",
+		"grafana-enterprise_v1.0.1-test_333_plan9_arm-6.ubi.docker.tar.gz":    "grafana-enterprise_v1.0.1-test_333_plan9_arm-6",
 	}
 
 	for k, v := range names {
@@ -3,16 +3,19 @@ package pipelines
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/retry"
 )
 
 // PublishPackage takes one or multiple grafana.tar.gz as input and publishes it to a set destination.
 func PublishPackage(ctx context.Context, d *dagger.Client, args PipelineArgs) error {
-	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts)
+	packages, err := containers.GetPackages(ctx, d, args.PackageInputOpts, args.GCPOpts, args.S3Opts)
 	if err != nil {
 		return err
 	}
@@ -22,7 +25,19 @@ func PublishPackage(ctx context.Context, d *dagger.Client, args PipelineArgs) er
 		c = c.WithFile("/dist/"+filepath.Base(name), packages[i])
 	}
 
-	dst, err := containers.PublishDirectory(ctx, d, c.Directory("dist"), args.GCPOpts, args.PublishOpts.Destination)
+	dst := args.PublishOpts.Destination
+	err = args.RetryPolicies.For(retry.PhasePublish).Do(ctx, slog.Default(), func(ctx context.Context) error {
+		out, err := containers.PublishDirectory(ctx, d, c.Directory("dist"), args.GCPOpts, args.S3Opts, args.PublishOpts.Destination)
+		if err == nil {
+			dst = out
+		}
+		return err
+	})
+
+	// Every package is published in the same directory upload, so they all succeed or fail together.
+	for _, name := range args.PackageInputOpts.Packages {
+		emitPublishEvent(filepath.Base(name), strings.TrimSuffix(dst, "/")+"/"+filepath.Base(name), err)
+	}
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-build/docker"
 	"github.com/grafana/grafana-build/gcom"
 	"github.com/grafana/grafana-build/gpg"
+	"github.com/grafana/grafana-build/retry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -72,8 +73,12 @@ type PipelineArgs struct {
 	GPGOpts          *gpg.GPGOpts
 	DockerOpts       *docker.DockerOpts
 	GCPOpts          *containers.GCPOpts
+	S3Opts           *containers.S3Opts
 	ConcurrencyOpts  *ConcurrencyOpts
 
+	// RetryPolicies are populated from the RetryFlags. Phases without flags use the default number of attempts.
+	RetryPolicies retry.Policies
+
 	// ProImageOpts will be populated if ProImageFlags are enabled on the current sub-command.
 	ProImageOpts *containers.ProImageOpts
 
@@ -101,6 +106,10 @@ func PipelineArgsFromContext(ctx context.Context, c cliutil.CLIContext) (Pipelin
 	if err != nil {
 		return PipelineArgs{}, err
 	}
+	retryPolicies, err := retry.PoliciesFromFlags(c)
+	if err != nil {
+		return PipelineArgs{}, err
+	}
 
 	return PipelineArgs{
 		Context:  c,
@@ -113,7 +122,9 @@ func PipelineArgsFromContext(ctx context.Context, c cliutil.CLIContext) (Pipelin
 		PackageInputOpts: containers.PackageInputOptsFromFlags(c),
 		DockerOpts:       DockerOptsFromFlags(c),
 		GCPOpts:          containers.GCPOptsFromFlags(c),
+		S3Opts:           containers.S3OptsFromFlags(c),
 		ConcurrencyOpts:  ConcurrencyOptsFromFlags(c),
+		RetryPolicies:    retryPolicies,
 		ProImageOpts:     containers.ProImageOptsFromFlags(c),
 		GCOMOpts:         gcomOpts,
 		NpmToken:         c.String("token"),
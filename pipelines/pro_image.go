@@ -3,7 +3,7 @@ package pipelines
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
@@ -14,14 +14,14 @@ func ProImage(ctx context.Context, dc *dagger.Client, args PipelineArgs) error {
 	if len(args.PackageInputOpts.Packages) > 1 {
 		return fmt.Errorf("only one package is allowed: packages=%+v", args.PackageInputOpts.Packages)
 	}
-	packages, err := containers.GetPackages(ctx, dc, args.PackageInputOpts, args.GCPOpts)
+	packages, err := containers.GetPackages(ctx, dc, args.PackageInputOpts, args.GCPOpts, args.S3Opts)
 	if err != nil {
 		return fmt.Errorf("getting packages: packages=%+v %w", args.PackageInputOpts.Packages, err)
 	}
 
 	debianPackageFile := packages[0]
 
-	log.Printf("Cloning hosted Grafana...")
+	slog.Info("Cloning hosted Grafana...")
 	hostedGrafanaRepo, err := git.CloneWithGitHubToken(dc, args.ProImageOpts.GitHubToken, "https://github.com/grafana/hosted-grafana.git", "main")
 	if err != nil {
 		return fmt.Errorf("cloning hosted-grafana repo: %w", err)
@@ -32,7 +32,7 @@ func ProImage(ctx context.Context, dc *dagger.Client, args PipelineArgs) error {
 
 	hostedGrafanaImage := fmt.Sprintf("%s/%s:%s", args.ProImageOpts.ContainerRegistry, args.ProImageOpts.Repo, args.ProImageOpts.ImageTag)
 
-	log.Printf("Building hosted Grafana image: %s", hostedGrafanaImage)
+	slog.Info("Building hosted Grafana image", "image", hostedGrafanaImage)
 	container := dc.Container().From("google/cloud-sdk:433.0.0-alpine").
 		WithExec([]string{
 			"/bin/sh", "-c",
@@ -70,7 +70,7 @@ func ProImage(ctx context.Context, dc *dagger.Client, args PipelineArgs) error {
 			return fmt.Errorf("authenticating container with gcs auth: %w", err)
 		}
 
-		log.Printf("Pushing hosted Grafana image to registry...")
+		slog.Info("Pushing hosted Grafana image to registry...")
 		container = authenticatedContainer.WithExec([]string{
 			"/bin/sh", "-c",
 			fmt.Sprintf("docker push %s", hostedGrafanaImage),
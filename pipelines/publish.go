@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/events"
+	"github.com/grafana/grafana-build/retry"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -36,44 +39,73 @@ func (w *SyncWriter) Write(b []byte) (int, error) {
 
 var Stdout = NewSyncWriter(os.Stdout)
 
-func PublishFileFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, opts *containers.PublishFileOpts) func() error {
+// emitPublishEvent emits an 'artifact-published' event, or an 'artifact-failed' event if err is not nil.
+func emitPublishEvent(filename, destination string, err error) {
+	e := events.Event{
+		Type:        events.TypePublished,
+		Filename:    filename,
+		Destination: destination,
+	}
+	if err != nil {
+		e.Type = events.TypeFailed
+		e.Action = "publish"
+	}
+
+	events.Emit(e.WithError(err))
+}
+
+// PublishFileFunc returns a function that publishes a file to GCS, S3, or the local filesystem, and retries with the policy when the upload fails with a transient error.
+func PublishFileFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, opts *containers.PublishFileOpts, policy retry.Policy) func() error {
 	return func() error {
-		log.Printf("[%s] Attempting to publish file", opts.Destination)
-		log.Printf("[%s] Acquiring semaphore", opts.Destination)
+		slog.Info("Attempting to publish file", "destination", opts.Destination)
+		slog.Info("Acquiring semaphore", "destination", opts.Destination)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", opts.Destination)
+		slog.Info("Acquired semaphore", "destination", opts.Destination)
 
-		log.Printf("[%s] Publishing file", opts.Destination)
-		out, err := containers.PublishFile(ctx, d, opts)
+		slog.Info("Publishing file", "destination", opts.Destination)
+		var out []string
+		err := policy.Do(ctx, slog.With("destination", opts.Destination), func(ctx context.Context) error {
+			o, err := containers.PublishFile(ctx, d, opts)
+			out = o
+			return err
+		})
+		emitPublishEvent(filepath.Base(opts.Destination), opts.Destination, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", opts.Destination, err)
 		}
-		log.Printf("[%s] Done publishing file", opts.Destination)
+		slog.Info("Done publishing file", "destination", opts.Destination)
 
 		fmt.Fprintln(Stdout, strings.Join(out, "\n"))
 		return nil
 	}
 }
 
-func PublishDirFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, dir *dagger.Directory, opts *containers.GCPOpts, dst string) func() error {
+// PublishDirFunc returns a function that publishes a directory to GCS, S3, or the local filesystem, and retries with the policy when the upload fails with a transient error.
+func PublishDirFunc(ctx context.Context, sm *semaphore.Weighted, d *dagger.Client, dir *dagger.Directory, opts *containers.GCPOpts, s3Opts *containers.S3Opts, dst string, policy retry.Policy) func() error {
 	return func() error {
-		log.Printf("[%s] Attempting to publish file", dst)
-		log.Printf("[%s] Acquiring semaphore", dst)
+		slog.Info("Attempting to publish file", "destination", dst)
+		slog.Info("Acquiring semaphore", "destination", dst)
 		if err := sm.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
 		}
 		defer sm.Release(1)
-		log.Printf("[%s] Acquired semaphore", dst)
+		slog.Info("Acquired semaphore", "destination", dst)
 
-		log.Printf("[%s] Publishing file", dst)
-		out, err := containers.PublishDirectory(ctx, d, dir, opts, dst)
+		slog.Info("Publishing file", "destination", dst)
+		var out string
+		err := policy.Do(ctx, slog.With("destination", dst), func(ctx context.Context) error {
+			o, err := containers.PublishDirectory(ctx, d, dir, opts, s3Opts, dst)
+			out = o
+			return err
+		})
+		emitPublishEvent(filepath.Base(dst), dst, err)
 		if err != nil {
 			return fmt.Errorf("[%s] error: %w", dst, err)
 		}
-		log.Printf("[%s] Done publishing file", dst)
+		slog.Info("Done publishing file", "destination", dst)
 
 		fmt.Fprintln(Stdout, out)
 		return nil
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+type timelineRow struct {
+	Span
+	Depth    int
+	Offset   float64
+	Width    float64
+	Critical bool
+}
+
+type htmlData struct {
+	*Report
+	Rows []timelineRow
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"indent":   func(depth int) int { return depth * 12 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>grafana-build report</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; margin: 20px; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+td, th { padding: 3px 8px; text-align: left; border-bottom: 1px solid #eee; white-space: nowrap; }
+.timeline td.bar { width: 100%; position: relative; }
+.bar div { position: absolute; top: 4px; height: 12px; min-width: 1px; background: #5794f2; }
+.bar div.critical { background: #ff9830; }
+.bar div.error { background: #f2495c; }
+</style>
+</head>
+<body>
+<h1>grafana-build report</h1>
+<table>
+<tr><th>Total duration</th><td>{{ duration .Duration }}</td></tr>
+<tr><th>Spans</th><td>{{ len .Spans }}</td></tr>
+<tr><th>Max parallelism</th><td>{{ .MaxParallelism }}</td></tr>
+<tr><th>Avg parallelism</th><td>{{ printf "%.2f" .AvgParallelism }}</td></tr>
+</table>
+
+<h2>Timeline</h2>
+<p>Spans on the critical path are orange, failed spans are red.</p>
+<table class="timeline">
+<tr><th>Span</th><th>Artifact</th><th>Duration</th><th></th></tr>
+{{- range .Rows }}
+<tr>
+<td style="padding-left: {{ indent .Depth }}px">{{ .Name }}</td>
+<td>{{ index .Attributes "artifact" }}</td>
+<td>{{ duration .Duration }}</td>
+<td class="bar"><div class="{{ if .Error }}error{{ else if .Critical }}critical{{ end }}" style="left: {{ printf "%.2f" .Offset }}%; width: {{ printf "%.2f" .Width }}%"></div></td>
+</tr>
+{{- end }}
+</table>
+
+<h2>Artifacts</h2>
+<table>
+<tr><th>Artifact</th><th>Action</th><th>Duration</th><th>Filename</th><th>Error</th></tr>
+{{- range .Artifacts }}
+<tr><td>{{ .Artifact }}</td><td>{{ .Action }}</td><td>{{ duration .Duration }}</td><td>{{ .Filename }}</td><td>{{ .Error }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a self-contained HTML page with a timeline of every span.
+func WriteHTML(w io.Writer, r *Report) error {
+	var (
+		parents  = make(map[string]string, len(r.Spans))
+		critical = make(map[string]bool, len(r.CriticalPath))
+		total    = float64(r.Duration())
+		rows     = make([]timelineRow, len(r.Spans))
+	)
+
+	for _, s := range r.Spans {
+		parents[s.SpanID] = s.ParentID
+	}
+	for _, s := range r.CriticalPath {
+		critical[s.SpanID] = true
+	}
+
+	for i, s := range r.Spans {
+		depth := 0
+		for p := parents[s.SpanID]; p != ""; p = parents[p] {
+			// The parent of a root span can be in another trace file if TRACEPARENT was set.
+			if _, ok := parents[p]; !ok {
+				break
+			}
+			depth++
+		}
+
+		row := timelineRow{
+			Span:     s,
+			Depth:    depth,
+			Critical: critical[s.SpanID],
+		}
+		if total != 0 {
+			row.Offset = float64(s.Start.Sub(r.Start)) / total * 100
+			row.Width = float64(s.Duration()) / total * 100
+		}
+		rows[i] = row
+	}
+
+	return htmlTemplate.Execute(w, htmlData{
+		Report: r,
+		Rows:   rows,
+	})
+}
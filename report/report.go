@@ -0,0 +1,189 @@
+package report
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrorNoSpans = errors.New("the trace file has no spans")
+
+// ArtifactTiming is how long a single action, like 'export' or 'verify', took for an artifact.
+type ArtifactTiming struct {
+	Artifact string
+	Filename string
+	Action   string
+	Duration time.Duration
+	Error    bool
+}
+
+// A Report summarizes the spans of a single grafana-build run.
+type Report struct {
+	Start time.Time
+	End   time.Time
+	// Spans are all of the spans sorted by their start time.
+	Spans []Span
+	// CriticalPath starts at the root span that finished last. Each following span is the child of the previous span that finished last.
+	// Speeding up spans that are not on the critical path does not make the build faster.
+	CriticalPath []Span
+	// Artifacts are the timings of every artifact action, longest first.
+	Artifacts []ArtifactTiming
+	// MaxParallelism is the highest number of artifact actions that ran at the same time, not counting 'build', which only adds the artifact to the dag.
+	MaxParallelism int
+	// AvgParallelism is the average number of artifact actions that were running while at least one was running.
+	AvgParallelism float64
+}
+
+func (r *Report) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// New creates a Report from the spans read from a trace file.
+func New(spans []Span) (*Report, error) {
+	if len(spans) == 0 {
+		return nil, ErrorNoSpans
+	}
+
+	spans = append([]Span{}, spans...)
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+
+	r := &Report{
+		Start: spans[0].Start,
+		End:   spans[0].End,
+		Spans: spans,
+	}
+
+	for _, s := range spans {
+		if s.End.After(r.End) {
+			r.End = s.End
+		}
+	}
+
+	r.CriticalPath = CriticalPath(spans)
+	r.Artifacts = artifactTimings(spans)
+	r.MaxParallelism, r.AvgParallelism = parallelism(actionSpans(spans))
+
+	return r, nil
+}
+
+// CriticalPath returns the chain of spans that finished last, starting from a root span.
+func CriticalPath(spans []Span) []Span {
+	var (
+		ids      = make(map[string]bool, len(spans))
+		children = map[string][]Span{}
+		roots    = []Span{}
+	)
+
+	for _, s := range spans {
+		ids[s.SpanID] = true
+	}
+	for _, s := range spans {
+		if s.ParentID == "" || !ids[s.ParentID] {
+			roots = append(roots, s)
+			continue
+		}
+		children[s.ParentID] = append(children[s.ParentID], s)
+	}
+
+	path := []Span{}
+	next := roots
+	for len(next) != 0 {
+		last := next[0]
+		for _, s := range next[1:] {
+			if s.End.After(last.End) {
+				last = s
+			}
+		}
+
+		path = append(path, last)
+		next = children[last.SpanID]
+	}
+
+	return path
+}
+
+// actionSpans returns the spans of the artifact actions that do the work of the build.
+func actionSpans(spans []Span) []Span {
+	s := []Span{}
+	for _, v := range spans {
+		if _, ok := v.Attributes["artifact"]; !ok || v.Name == "artifact.build" {
+			continue
+		}
+		s = append(s, v)
+	}
+
+	return s
+}
+
+func artifactTimings(spans []Span) []ArtifactTiming {
+	timings := []ArtifactTiming{}
+	for _, v := range spans {
+		artifact, ok := v.Attributes["artifact"]
+		if !ok || !strings.HasPrefix(v.Name, "artifact.") {
+			continue
+		}
+
+		timings = append(timings, ArtifactTiming{
+			Artifact: artifact,
+			Filename: v.Attributes["filename"],
+			Action:   strings.TrimPrefix(v.Name, "artifact."),
+			Duration: v.Duration(),
+			Error:    v.Error,
+		})
+	}
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+
+	return timings
+}
+
+// parallelism returns the most spans that were running at the same time, and the average number of spans that were running while at least one was.
+func parallelism(spans []Span) (int, float64) {
+	type event struct {
+		t     time.Time
+		delta int
+	}
+
+	events := make([]event, 0, len(spans)*2)
+	for _, s := range spans {
+		events = append(events, event{s.Start, 1}, event{s.End, -1})
+	}
+
+	// Ends are sorted before starts at the same time so that spans that run one after another don't count as parallel.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].t.Equal(events[j].t) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].t.Before(events[j].t)
+	})
+
+	var (
+		running int
+		max     int
+		busy    time.Duration
+		work    time.Duration
+	)
+	for i, e := range events {
+		if i != 0 && running != 0 {
+			d := e.t.Sub(events[i-1].t)
+			busy += d
+			work += d * time.Duration(running)
+		}
+
+		running += e.delta
+		if running > max {
+			max = running
+		}
+	}
+
+	if busy == 0 {
+		return max, float64(max)
+	}
+
+	return max, float64(work) / float64(busy)
+}
@@ -0,0 +1,158 @@
+package report_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-build/otel"
+	"github.com/grafana/grafana-build/report"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var t0 = time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func span(id, parent, name string, start, end int, attrs ...string) report.Span {
+	a := map[string]string{}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		a[attrs[i]] = attrs[i+1]
+	}
+
+	return report.Span{
+		SpanID:     id,
+		ParentID:   parent,
+		Name:       name,
+		Start:      t0.Add(time.Duration(start) * time.Second),
+		End:        t0.Add(time.Duration(end) * time.Second),
+		Attributes: a,
+	}
+}
+
+func testSpans() []report.Span {
+	return []report.Span{
+		span("1", "", "artifacts", 0, 100),
+		span("2", "1", "artifact.build", 0, 1, "artifact", "targz:linux/amd64"),
+		span("3", "1", "artifact.export", 1, 60, "artifact", "targz:linux/amd64"),
+		span("4", "1", "artifact.export", 1, 90, "artifact", "deb:linux/amd64"),
+		span("5", "1", "artifact.verify", 60, 99, "artifact", "targz:linux/amd64"),
+		span("6", "4", "dagger.exec", 10, 80),
+	}
+}
+
+func TestNew(t *testing.T) {
+	r, err := report.New(testSpans())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := r.Duration(); d != 100*time.Second {
+		t.Errorf("expected duration of 100s but got %s", d)
+	}
+
+	names := []string{}
+	for _, s := range r.CriticalPath {
+		names = append(names, s.SpanID)
+	}
+	if p := strings.Join(names, ","); p != "1,5" {
+		t.Errorf("expected critical path '1,5' but got '%s'", p)
+	}
+
+	if r.MaxParallelism != 2 {
+		t.Errorf("expected max parallelism of 2 but got %d", r.MaxParallelism)
+	}
+	// 59s + 89s + 39s of work while at least one action was running from 1s to 99s.
+	if expect := 187.0 / 98.0; r.AvgParallelism != expect {
+		t.Errorf("expected avg parallelism of %f but got %f", expect, r.AvgParallelism)
+	}
+
+	if len(r.Artifacts) != 4 {
+		t.Fatalf("expected 4 artifact timings but got %d", len(r.Artifacts))
+	}
+	if a := r.Artifacts[0]; a.Artifact != "deb:linux/amd64" || a.Action != "export" || a.Duration != 89*time.Second {
+		t.Errorf("expected the longest timing to be the deb export but got %+v", a)
+	}
+}
+
+func TestNewNoSpans(t *testing.T) {
+	if _, err := report.New(nil); err != report.ErrorNoSpans {
+		t.Errorf("expected ErrorNoSpans but got %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r, err := report.New(testSpans())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := &bytes.Buffer{}
+	if err := report.WriteText(text, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "Max parallelism:  2") {
+		t.Errorf("expected text report to contain the max parallelism but got:\n%s", text.String())
+	}
+
+	html := &bytes.Buffer{}
+	if err := report.WriteHTML(html, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "deb:linux/amd64") {
+		t.Errorf("expected html report to contain the artifacts")
+	}
+}
+
+func TestReadSpans(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "trace.json")
+	)
+
+	exporter, err := otlptrace.New(ctx, otel.NewFileClient(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tp.Tracer("test")
+
+	ctx, parent := tracer.Start(ctx, "artifacts")
+	_, child := tracer.Start(ctx, "artifact.export", trace.WithAttributes(
+		attribute.String("artifact", "targz:linux/amd64"),
+		attribute.Bool("cache.hit", true),
+	))
+	child.End()
+	parent.End()
+
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	spans, err := report.ReadSpans(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans but got %d", len(spans))
+	}
+
+	s := spans[0]
+	if s.Name != "artifact.export" || s.ParentID != spans[1].SpanID {
+		t.Errorf("expected the export span to be a child of the artifacts span but got %+v", s)
+	}
+	if s.Attributes["artifact"] != "targz:linux/amd64" || s.Attributes["cache.hit"] != "true" {
+		t.Errorf("unexpected attributes %v", s.Attributes)
+	}
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Span is a finished span read from a trace file.
+type Span struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Name     string
+	Start    time.Time
+	End      time.Time
+	// Attributes are the attributes of the span with their values formatted as strings.
+	Attributes map[string]string
+	Error      bool
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// maxLineSize is the largest batch of spans that ReadSpans can read from a single line.
+const maxLineSize = 64 * 1024 * 1024
+
+// ReadSpans reads the spans from a trace file, where every line is an OTLP JSON 'ExportTraceServiceRequest'.
+// This is the format written by the '--trace-file' flag and by the OpenTelemetry Collector's file exporter.
+func ReadSpans(r io.Reader) ([]Span, error) {
+	var (
+		spans   = []Span{}
+		scanner = bufio.NewScanner(r)
+		line    = 0
+	)
+	scanner.Buffer(nil, maxLineSize)
+
+	for scanner.Scan() {
+		line++
+		b := scanner.Bytes()
+		if len(b) == 0 {
+			continue
+		}
+
+		req := &collectortracepb.ExportTraceServiceRequest{}
+		if err := protojson.Unmarshal(b, req); err != nil {
+			return nil, fmt.Errorf("error parsing spans on line %d: %w", line, err)
+		}
+
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					spans = append(spans, spanFromProto(s))
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return spans, nil
+}
+
+func spanFromProto(s *tracepb.Span) Span {
+	attrs := make(map[string]string, len(s.Attributes))
+	for _, v := range s.Attributes {
+		attrs[v.Key] = formatValue(v.Value)
+	}
+
+	return Span{
+		TraceID:    hex.EncodeToString(s.TraceId),
+		SpanID:     hex.EncodeToString(s.SpanId),
+		ParentID:   hex.EncodeToString(s.ParentSpanId),
+		Name:       s.Name,
+		Start:      time.Unix(0, int64(s.StartTimeUnixNano)),
+		End:        time.Unix(0, int64(s.EndTimeUnixNano)),
+		Attributes: attrs,
+		Error:      s.Status.GetCode() == tracepb.Status_STATUS_CODE_ERROR,
+	}
+}
+
+func formatValue(v *commonpb.AnyValue) string {
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return val.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(val.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(val.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(val.DoubleValue, 'f', -1, 64)
+	}
+
+	return v.String()
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// WriteText writes the report as plain text tables.
+func WriteText(w io.Writer, r *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Total duration:\t%s\n", formatDuration(r.Duration()))
+	fmt.Fprintf(tw, "Spans:\t%d\n", len(r.Spans))
+	fmt.Fprintf(tw, "Max parallelism:\t%d\n", r.MaxParallelism)
+	fmt.Fprintf(tw, "Avg parallelism:\t%.2f\n", r.AvgParallelism)
+
+	fmt.Fprintln(tw, "\nCRITICAL PATH\tSTART\tDURATION\tARTIFACT")
+	for _, s := range r.CriticalPath {
+		fmt.Fprintf(tw, "%s\t+%s\t%s\t%s\n", s.Name, formatDuration(s.Start.Sub(r.Start)), formatDuration(s.Duration()), s.Attributes["artifact"])
+	}
+
+	fmt.Fprintln(tw, "\nARTIFACT\tACTION\tDURATION\tFILENAME\tERROR")
+	for _, a := range r.Artifacts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", a.Artifact, a.Action, formatDuration(a.Duration), a.Filename, a.Error)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"dagger.io/dagger"
+)
+
+// RetryablePatterns are case-insensitive substrings of command output or error messages that indicate a transient failure,
+// usually from the network. Failures without any of these, like compiler errors or failing tests, are not retried.
+var RetryablePatterns = []string{
+	// Network errors from Go, git, curl, and the cloud CLIs
+	"connection reset by peer",
+	"connection refused",
+	"connection timed out",
+	"i/o timeout",
+	"tls handshake timeout",
+	"temporary failure in name resolution",
+	"no such host",
+	"could not resolve host",
+	"network is unreachable",
+	"unexpected eof",
+	"broken pipe",
+	// git
+	"rpc failed",
+	"early eof",
+	"the remote end hung up unexpectedly",
+	// yarn and npm
+	"econnreset",
+	"etimedout",
+	"eai_again",
+	"socket hang up",
+	"network connection",
+	// HTTP status codes from registries, GCS, and S3
+	"429 too many requests",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"slowdown",
+	"rate limit",
+}
+
+// IsRetryable returns true if the error looks transient. If the error is, or wraps, a *dagger.ExecError, then the stdout and stderr of the command are checked.
+// Cancelled contexts are never retried.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var (
+		output string
+		e      *dagger.ExecError
+	)
+	if errors.As(err, &e) {
+		output = strings.Join([]string{e.Stdout, e.Stderr}, "\n")
+	} else {
+		output = err.Error()
+	}
+
+	output = strings.ToLower(output)
+	for _, p := range RetryablePatterns {
+		if strings.Contains(output, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Summary returns the first line of the error, which is used where the full output of a failed command would be too long, like in log lines and tables.
+func Summary(err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	return line
+}
@@ -0,0 +1,55 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/retry"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		expect bool
+	}{
+		"git clone": {
+			err:    &dagger.ExecError{Stderr: "error: RPC failed; curl 56 GnuTLS recv error (-9)\nfatal: early EOF"},
+			expect: true,
+		},
+		"go mod download": {
+			err:    fmt.Errorf("building backend: %w", &dagger.ExecError{Stderr: "go: github.com/grafana/grafana-plugin-sdk-go@v0.180.0: Get \"https://proxy.golang.org/...\": dial tcp: lookup proxy.golang.org: Temporary failure in name resolution"}),
+			expect: true,
+		},
+		"yarn install": {
+			err:    &dagger.ExecError{Stdout: "error An unexpected error occurred: \"https://registry.yarnpkg.com/react: ETIMEDOUT\"."},
+			expect: true,
+		},
+		"gcs upload": {
+			err:    errors.New("ServiceException: 503 Service Unavailable"),
+			expect: true,
+		},
+		"compile error": {
+			err:    &dagger.ExecError{Stderr: "pkg/api/api.go:12:2: undefined: foo"},
+			expect: false,
+		},
+		"cancelled": {
+			err:    fmt.Errorf("connection reset by peer: %w", context.Canceled),
+			expect: false,
+		},
+		"nil": {
+			err:    nil,
+			expect: false,
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			if r := retry.IsRetryable(v.err); r != v.expect {
+				t.Errorf("expected IsRetryable to be %t but got %t", v.expect, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-build/cliutil"
+)
+
+// AttemptsFlag returns the name of the flag that sets the number of attempts of the phase, like '--export-attempts'.
+func AttemptsFlag(phase Phase) string {
+	return fmt.Sprintf("%s-attempts", phase)
+}
+
+// PoliciesFromFlags reads the '--<phase>-attempts', '--retry-backoff', and '--retry-max-backoff' flags.
+// Phases without a flag use their DefaultAttempts.
+func PoliciesFromFlags(c cliutil.CLIContext) (Policies, error) {
+	backoff, err := durationFlag(c, "retry-backoff", DefaultBackoff)
+	if err != nil {
+		return nil, err
+	}
+	maxBackoff, err := durationFlag(c, "retry-max-backoff", DefaultMaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(Policies, len(Phases))
+	for _, phase := range Phases {
+		attempts := c.Int64(AttemptsFlag(phase))
+		if attempts == 0 {
+			attempts = DefaultAttempts[phase]
+		}
+
+		policies[phase] = Policy{
+			Attempts:   int(attempts),
+			Backoff:    backoff,
+			MaxBackoff: maxBackoff,
+		}
+	}
+
+	return policies, nil
+}
+
+func durationFlag(c cliutil.CLIContext, name string, def time.Duration) (time.Duration, error) {
+	v := c.String(name)
+	if v == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for '--%s': %w", name, err)
+	}
+
+	return d, nil
+}
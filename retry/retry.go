@@ -0,0 +1,134 @@
+// Package retry retries the phases of a build, like exporting or publishing an artifact, when they fail with errors that are likely to be transient.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// A Phase is a step of a build that has its own retry policy.
+type Phase string
+
+const (
+	PhaseExport  Phase = "export"
+	PhaseVerify  Phase = "verify"
+	PhaseScan    Phase = "scan"
+	PhasePublish Phase = "publish"
+)
+
+var Phases = []Phase{PhaseExport, PhaseVerify, PhaseScan, PhasePublish}
+
+// DefaultAttempts is the number of times each phase is attempted if no '--<phase>-attempts' flag is set.
+// Building only adds artifacts to the dag and isn't retried; the work that can fail happens when exporting.
+var DefaultAttempts = map[Phase]int64{
+	PhaseExport:  3,
+	PhaseVerify:  2,
+	PhaseScan:    2,
+	PhasePublish: 3,
+}
+
+const (
+	DefaultBackoff    = 10 * time.Second
+	DefaultMaxBackoff = 2 * time.Minute
+)
+
+// Error is returned by Policy.Do when every attempt failed or the error was not retryable.
+type Error struct {
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Attempts == 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("failed after %d attempts: %s", e.Attempts, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Attempts returns the number of attempts in a retry Error, or 1 if err is not a retry Error.
+func Attempts(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Attempts
+	}
+	return 1
+}
+
+// A Policy decides how many times and how often a function is attempted.
+type Policy struct {
+	// Attempts is the maximum number of times that the function is called. Values lower than 1 are treated as 1.
+	Attempts int
+	// Backoff is the time to wait after the first failed attempt. It doubles after every attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retryable decides if an error should be retried. If it is nil, then IsRetryable is used.
+	Retryable func(error) bool
+}
+
+func (p Policy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff != 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+
+	if p.MaxBackoff != 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable, or the attempts run out.
+// If fn never succeeds, then the last error is returned wrapped in an *Error.
+func (p Policy) Do(ctx context.Context, log *slog.Logger, fn func(context.Context) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	attempts := p.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= attempts || !retryable(err) {
+			return &Error{Attempts: attempt, Err: err}
+		}
+
+		wait := p.backoff(attempt)
+		log.Warn("Attempt failed with a retryable error", "attempt", attempt, "attempts", attempts, "backoff", wait, "error", Summary(err))
+
+		select {
+		case <-ctx.Done():
+			return &Error{Attempts: attempt, Err: errors.Join(err, ctx.Err())}
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Policies are the retry policies of each phase.
+type Policies map[Phase]Policy
+
+// For returns the policy of the phase. Phases without a policy are only attempted once.
+func (p Policies) For(phase Phase) Policy {
+	if policy, ok := p[phase]; ok {
+		return policy
+	}
+
+	return Policy{Attempts: 1}
+}
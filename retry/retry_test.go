@@ -0,0 +1,92 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-build/retry"
+)
+
+var (
+	discard   = slog.New(slog.NewTextHandler(io.Discard, nil))
+	transient = errors.New("dial tcp: i/o timeout")
+	permanent = errors.New("undefined: foo")
+)
+
+func TestPolicyDo(t *testing.T) {
+	cases := map[string]struct {
+		attempts int
+		errs     []error
+		calls    int
+		fail     bool
+	}{
+		"succeeds first time":         {attempts: 3, errs: []error{nil}, calls: 1},
+		"succeeds after retry":        {attempts: 3, errs: []error{transient, nil}, calls: 2},
+		"runs out of attempts":        {attempts: 3, errs: []error{transient, transient, transient}, calls: 3, fail: true},
+		"does not retry permanent":    {attempts: 3, errs: []error{permanent}, calls: 1, fail: true},
+		"zero attempts runs once":     {attempts: 0, errs: []error{transient}, calls: 1, fail: true},
+		"stops retrying on permanent": {attempts: 5, errs: []error{transient, permanent}, calls: 2, fail: true},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			calls := 0
+			err := retry.Policy{Attempts: v.attempts}.Do(context.Background(), discard, func(ctx context.Context) error {
+				err := v.errs[calls]
+				calls++
+				return err
+			})
+
+			if calls != v.calls {
+				t.Errorf("expected %d calls but got %d", v.calls, calls)
+			}
+			if (err != nil) != v.fail {
+				t.Fatalf("expected failure to be %t but got %v", v.fail, err)
+			}
+			if err != nil {
+				if n := retry.Attempts(err); n != v.calls {
+					t.Errorf("expected the error to have %d attempts but got %d", v.calls, n)
+				}
+				if !errors.Is(err, v.errs[calls-1]) {
+					t.Errorf("expected the error to wrap the last error but got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyDoCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := retry.Policy{Attempts: 3, Backoff: time.Hour}
+
+	calls := 0
+	err := policy.Do(ctx, discard, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return transient
+	})
+
+	if calls != 1 {
+		t.Errorf("expected 1 call but got %d", calls)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled error but got %v", err)
+	}
+}
+
+func TestPoliciesFor(t *testing.T) {
+	policies := retry.Policies{
+		retry.PhaseExport: {Attempts: 5},
+	}
+
+	if n := policies.For(retry.PhaseExport).Attempts; n != 5 {
+		t.Errorf("expected 5 attempts but got %d", n)
+	}
+	if n := policies.For(retry.PhasePublish).Attempts; n != 1 {
+		t.Errorf("expected phases without a policy to have 1 attempt but got %d", n)
+	}
+}
@@ -0,0 +1,22 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities is the list of vulnerability severities in ascending order.
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// SeveritiesAtOrAbove returns the list of severities that are equal to or more severe than the threshold.
+// The threshold is case-insensitive.
+func SeveritiesAtOrAbove(threshold string) ([]string, error) {
+	threshold = strings.ToUpper(threshold)
+	for i, v := range Severities {
+		if v == threshold {
+			return Severities[i:], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unrecognized severity '%s'. Must be one of [%s]", threshold, strings.Join(Severities, ", "))
+}
@@ -0,0 +1,31 @@
+package scan_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/grafana/grafana-build/scan"
+)
+
+func TestSeveritiesAtOrAbove(t *testing.T) {
+	cases := map[string][]string{
+		"UNKNOWN":  {"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"},
+		"medium":   {"MEDIUM", "HIGH", "CRITICAL"},
+		"HIGH":     {"HIGH", "CRITICAL"},
+		"Critical": {"CRITICAL"},
+	}
+
+	for k, v := range cases {
+		s, err := scan.SeveritiesAtOrAbove(k)
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %s", k, err)
+		}
+		if !slices.Equal(s, v) {
+			t.Errorf("expected severities at or above '%s' to be %v but got %v", k, v, s)
+		}
+	}
+
+	if _, err := scan.SeveritiesAtOrAbove("SEVERE"); err == nil {
+		t.Errorf("expected an error for an unrecognized severity")
+	}
+}
@@ -0,0 +1,180 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dagger.io/dagger"
+	"github.com/grafana/grafana-build/containers"
+)
+
+const (
+	// TrivyImage is the default scanner image.
+	TrivyImage = "aquasec/trivy:0.45.1"
+
+	// cacheDir is where the vulnerability database is mounted when the database is provided with Opts.DB.
+	cacheDir = "/trivy"
+)
+
+type Format string
+
+const (
+	FormatSARIF Format = "sarif"
+	FormatJSON  Format = "json"
+)
+
+// Formats is the list of supported report formats.
+var Formats = []Format{FormatSARIF, FormatJSON}
+
+// Opts controls how an artifact is scanned.
+type Opts struct {
+	// Image is the trivy image used for scanning.
+	Image string
+	// Severity is the minimum severity that will cause the scan to fail. Vulnerabilities with a lower severity are still
+	// included in the report.
+	Severity string
+	// Format is the format of the exported report.
+	Format Format
+	// DB is an optional trivy cache directory that contains a pre-downloaded vulnerability database ('db/trivy.db').
+	// When it is set, trivy will not attempt to download or update the database, which allows scanning without network access.
+	DB *dagger.Directory
+}
+
+// Report is the result of a scan.
+type Report struct {
+	// File is the formatted report.
+	File *dagger.File
+	// Vulnerabilities are the vulnerabilities found at or above the configured severity.
+	Vulnerabilities []Vulnerability
+}
+
+// ParseFormat returns the Format that matches the string, or an error if the format is not supported.
+func ParseFormat(s string) (Format, error) {
+	for _, v := range Formats {
+		if string(v) == s {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("unrecognized report format '%s'. Must be one of %v", s, Formats)
+}
+
+// Ext returns the file extension used for reports in the given format.
+func (f Format) Ext() string {
+	return "trivy." + string(f)
+}
+
+// TrivyContainer returns a container with trivy installed, and the vulnerability database mounted if one was provided.
+func TrivyContainer(d *dagger.Client, opts *Opts) *dagger.Container {
+	image := opts.Image
+	if image == "" {
+		image = TrivyImage
+	}
+
+	c := d.Container().From(image).WithEntrypoint([]string{})
+	if opts.DB != nil {
+		c = c.WithMountedDirectory(cacheDir, opts.DB)
+	}
+
+	return c
+}
+
+func trivyArgs(command, target string, opts *Opts) []string {
+	args := []string{"trivy", command, "--format", "json", "--output", "/report.json", "--exit-code", "0", "--quiet"}
+	if opts.DB != nil {
+		args = append(args, "--cache-dir", cacheDir, "--skip-db-update", "--skip-java-db-update", "--offline-scan")
+	}
+
+	return append(args, target)
+}
+
+// Image scans the image tarball (created with 'docker save').
+func Image(ctx context.Context, d *dagger.Client, image *dagger.File, opts *Opts) (*Report, error) {
+	c := TrivyContainer(d, opts).
+		WithMountedFile("/src/image.tar.gz", image).
+		WithExec(trivyArgs("image", "--input=/src/image.tar.gz", opts))
+
+	return report(ctx, c, opts)
+}
+
+// Rootfs scans the directory as a root filesystem. Go binaries in the directory are scanned using their embedded module information.
+func Rootfs(ctx context.Context, d *dagger.Client, dir *dagger.Directory, opts *Opts) (*Report, error) {
+	c := TrivyContainer(d, opts).
+		WithMountedDirectory("/src", dir).
+		WithExec(trivyArgs("rootfs", "/src", opts))
+
+	return report(ctx, c, opts)
+}
+
+func report(ctx context.Context, c *dagger.Container, opts *Opts) (*Report, error) {
+	severities, err := SeveritiesAtOrAbove(opts.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = containers.ExitError(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := c.File("/report.json").Contents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scan results: %w", err)
+	}
+
+	vulns, err := Vulnerabilities([]byte(contents), severities)
+	if err != nil {
+		return nil, err
+	}
+
+	file := c.File("/report.json")
+	if opts.Format == FormatSARIF {
+		file = c.
+			WithExec([]string{"trivy", "convert", "--format", "sarif", "--output", "/report.sarif", "/report.json"}).
+			File("/report.sarif")
+	}
+
+	return &Report{
+		File:            file,
+		Vulnerabilities: vulns,
+	}, nil
+}
+
+// Vulnerability is a single vulnerability found in a trivy JSON report.
+type Vulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgName          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+}
+
+type trivyReport struct {
+	Results []struct {
+		Target          string          `json:"Target"`
+		Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// Vulnerabilities parses a trivy JSON report and returns the vulnerabilities whose severity is in the given list.
+func Vulnerabilities(report []byte, severities []string) ([]Vulnerability, error) {
+	r := trivyReport{}
+	if err := json.Unmarshal(report, &r); err != nil {
+		return nil, fmt.Errorf("error parsing trivy report: %w", err)
+	}
+
+	vulns := []Vulnerability{}
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			for _, s := range severities {
+				if v.Severity == s {
+					vulns = append(vulns, v)
+					break
+				}
+			}
+		}
+	}
+
+	return vulns, nil
+}
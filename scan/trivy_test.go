@@ -0,0 +1,50 @@
+package scan_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/grafana/grafana-build/scan"
+)
+
+func TestVulnerabilities(t *testing.T) {
+	report := []byte(`{
+  "Results": [
+    {
+      "Target": "grafana",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2023-0001", "PkgName": "golang.org/x/net", "Severity": "MEDIUM"},
+        {"VulnerabilityID": "CVE-2023-0002", "PkgName": "stdlib", "Severity": "CRITICAL"}
+      ]
+    },
+    {
+      "Target": "grafana-cli"
+    },
+    {
+      "Target": "alpine",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2023-0003", "PkgName": "libcrypto3", "Severity": "HIGH"}
+      ]
+    }
+  ]
+}`)
+
+	severities, err := scan.SeveritiesAtOrAbove("HIGH")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vulns, err := scan.Vulnerabilities(report, severities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for _, v := range vulns {
+		ids = append(ids, v.VulnerabilityID)
+	}
+
+	if expect := []string{"CVE-2023-0002", "CVE-2023-0003"}; !slices.Equal(ids, expect) {
+		t.Errorf("expected vulnerabilities %v but got %v", expect, ids)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +13,7 @@ import (
 	"dagger.io/dagger"
 	"github.com/grafana/grafana-build/backend"
 	"github.com/grafana/grafana-build/containers"
+	"github.com/grafana/grafana-build/docker"
 	"github.com/grafana/grafana-build/pipelines"
 )
 
@@ -36,7 +37,7 @@ const (
 
 	// 1: ersion
 	// 2. name (grafana-oss | grafana-enterprise)
-	// 3: '-ubuntu', if set
+	// 3: The base image suffix, like '-ubuntu', if set
 	// 4: arch
 	// 5: '.sha256', if set
 	dockerFormat = "artifacts/docker/%[1]s/%[2]s-%[1]s%[3]s-%[4]s.img%[5]s"
@@ -91,6 +92,13 @@ func ZipHandler(name string) []string {
 	return files
 }
 
+// rpmPublishedArch has the distributions whose rpm packages are published with a different architecture than the one in the package,
+// for compatibility with the packages that were already published.
+var rpmPublishedArch = map[backend.Distribution]string{
+	backend.DistLinuxARMv6: "arm",
+	backend.DistLinuxARMv7: "armhfp",
+}
+
 func RPMHandler(name string) []string {
 	ext := filepath.Ext(name)
 
@@ -113,19 +121,11 @@ func RPMHandler(name string) []string {
 	}
 
 	goos, arch := backend.OSAndArch(opts.Distro)
-	arm := backend.ArchVersion(opts.Distro)
-	if arch == "arm" {
-		if arm == "7" {
-			arch = "armhfp"
-		}
-	}
-
-	if arch == "arm64" {
-		arch = "aarch64"
-	}
-
-	if arch == "amd64" {
-		arch = "x86_64"
+	if a, ok := rpmPublishedArch[opts.Distro]; ok {
+		arch = a
+	} else if a := backend.PackageArch(opts.Distro, "rpm"); a != "" {
+		// The other architectures use their name in the rpm package, like 'x86_64' for amd64 or 'loongarch64' for loong64.
+		arch = a
 	}
 
 	enterprise2 := ""
@@ -215,10 +215,9 @@ func DebHandler(name string) []string {
 
 	names := []string{fullName}
 	goos, arch := backend.OSAndArch(opts.Distro)
-	if arch == "arm" {
-		arch = "armhf"
-		// If we're building for arm then we also copy the same thing, but with the name '-rpi'. for osme reason?
-		names = []string{fullName}
+	if a := backend.PackageArch(opts.Distro, "deb"); a != "" {
+		// Every architecture uses its name in the deb package, like 'armhf' for arm or 'ppc64el' for ppc64le.
+		arch = a
 	}
 
 	dst := []string{}
@@ -261,7 +260,8 @@ func TarGZHandler(name string) []string {
 	libc := []string{""}
 	goos, arch := backend.OSAndArch(opts.Distro)
 
-	if arch == "arm64" || arch == "arm" || arch == "amd64" && goos == "linux" {
+	// FreeBSD tarballs are linked against the FreeBSD libc, so they don't have musl copies.
+	if goos != "freebsd" && (arch == "arm64" || arch == "arm" || arch == "amd64" && goos == "linux") {
 		libc = []string{"", "-musl"}
 	}
 
@@ -298,9 +298,9 @@ func DockerHandler(name string) []string {
 	n := filepath.Base(name) // Surprisingly still works even with 'gs://' urls
 
 	// try to get .ubuntu.docker.tar.gz.sha256 / .ubuntu.docker.tar.gz / docker.tar.gz to all just end in 'tar.gz'
+	variant := docker.VariantFromFileName(n)
 	normalized := strings.ReplaceAll(n, sha256Ext, "")
-	normalized = strings.ReplaceAll(normalized, ".ubuntu", "")
-	normalized = strings.ReplaceAll(normalized, ".docker", "")
+	normalized = strings.TrimSuffix(normalized, variant.Ext) + "tar.gz"
 
 	opts := pipelines.TarOptsFromFileName(normalized)
 
@@ -315,17 +315,13 @@ func DockerHandler(name string) []string {
 	}
 
 	fullName += "-" + edition
-	ubuntu := ""
-	if strings.Contains(name, "ubuntu") {
-		ubuntu = "-ubuntu"
-	}
 
 	_, arch := backend.OSAndArch(opts.Distro)
 	if arch == "arm" {
 		arch += "v" + backend.ArchVersion(opts.Distro)
 	}
 	return []string{
-		fmt.Sprintf(dockerFormat, strings.TrimPrefix(opts.Version, "v"), fullName, ubuntu, arch, sha256),
+		fmt.Sprintf(dockerFormat, strings.TrimPrefix(opts.Version, "v"), fullName, variant.Suffix, arch, sha256),
 	}
 }
 
@@ -391,11 +387,11 @@ func main() {
 				dir := filepath.Join(prefix, filepath.Dir(v))
 				v := filepath.Join(prefix, v)
 
-				log.Println("Creating dir", dir)
+				slog.Info("Creating directory", "dir", dir)
 				if err := os.MkdirAll(dir, 0700); err != nil {
 					panic(err)
 				}
-				log.Println("Copying", name, "to", v)
+				slog.Info("Copying", "name", name, "destination", v)
 				//nolint:gosec
 				cmd := exec.Command("cp", "-r", strings.TrimPrefix(name, "file://"), v)
 				cmd.Stdout = os.Stdout
@@ -407,16 +403,16 @@ func main() {
 			continue
 		}
 
-		log.Println("File:", name, "to be copied as", destinations)
+		slog.Info("Copying file", "name", name, "destinations", destinations)
 		for _, v := range destinations {
 			dir := filepath.Join(prefix, filepath.Dir(v))
 			v := filepath.Join(prefix, v)
-			log.Println("Creating directory", dir)
+			slog.Info("Creating directory", "dir", dir)
 			if err := os.MkdirAll(dir, 0700); err != nil {
 				panic(err)
 			}
 
-			log.Println("Copying", name, "to", dir, "as", v)
+			slog.Info("Copying", "name", name, "dir", dir, "destination", v)
 
 			//nolint:gosec
 			cmd := exec.Command("cp", strings.TrimPrefix(name, "file://"), v)
@@ -428,7 +424,7 @@ func main() {
 		}
 	}
 
-	log.Println("Copying", prefix, "to gcs")
+	slog.Info("Copying to gcs", "prefix", prefix)
 	dst := os.Getenv("DESTINATION")
 	container = container.WithMountedDirectory("dist", client.Host().Directory(prefix)).
 		WithExec([]string{"gcloud", "storage", "cp", "-r", "/dist/*", dst})
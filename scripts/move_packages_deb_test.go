@@ -127,4 +127,22 @@ var debMapping = map[string]m{
 			"artifacts/downloads-enterprise2/v1.2.3-pre.4/enterprise2/release/grafana-enterprise2_1.2.3~pre.4_amd64.deb.sha256",
 		},
 	},
+	"OSS: Linux PPC64LE": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_ppc64le.deb",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana_1.2.3_ppc64el.deb",
+		},
+	},
+	"OSS: Linux S390X": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_s390x.deb",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana_1.2.3_s390x.deb",
+		},
+	},
+	"OSS: Linux LOONG64": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_loong64.deb",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana_1.2.3_loong64.deb",
+		},
+	},
 }
@@ -181,4 +181,22 @@ var dockerMapping = map[string]m{
 			"artifacts/docker/1.2.3/grafana-enterprise2-1.2.3-ubuntu-armv7.img",
 		},
 	},
+	"ENT: Linux AMD64 UBI": {
+		input: "gs://bucket/tag/grafana-enterprise_v1.2.3_102_linux_amd64.ubi.docker.tar.gz",
+		output: []string{
+			"artifacts/docker/1.2.3/grafana-enterprise-1.2.3-ubi-amd64.img",
+		},
+	},
+	"ENT: Linux AMD64 UBI SHA256": {
+		input: "gs://bucket/tag/grafana-enterprise_v1.2.3_102_linux_amd64.ubi.docker.tar.gz.sha256",
+		output: []string{
+			"artifacts/docker/1.2.3/grafana-enterprise-1.2.3-ubi-amd64.img.sha256",
+		},
+	},
+	"OSS: Linux ARM64 Distroless": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_arm64.distroless.docker.tar.gz",
+		output: []string{
+			"artifacts/docker/1.2.3/grafana-oss-1.2.3-distroless-arm64.img",
+		},
+	},
 }
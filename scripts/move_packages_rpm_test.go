@@ -85,4 +85,34 @@ var rpmMapping = map[string]m{
 			"artifacts/downloads-enterprise2/v1.2.3-pre.4/enterprise2/release/grafana-enterprise2-1.2.3~pre.4-1.x86_64.rpm.sha256",
 		},
 	},
+	"OSS: Linux PPC64LE": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_ppc64le.rpm",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3-1.ppc64le.rpm",
+		},
+	},
+	"OSS: Linux LOONG64": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_loong64.rpm",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3-1.loongarch64.rpm",
+		},
+	},
+	"OSS: Linux ARM6": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_arm-6.rpm",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3-1.arm.rpm",
+		},
+	},
+	"OSS: Linux S390X": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_s390x.rpm",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3-1.s390x.rpm",
+		},
+	},
+	"OSS: Linux RISCV64": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_riscv64.rpm",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3-1.riscv64.rpm",
+		},
+	},
 }
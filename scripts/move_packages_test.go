@@ -142,6 +142,32 @@ var targzMapping = map[string]m{
 			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.darwin-amd64.tar.gz.sha256",
 		},
 	},
+	"OSS: Darwin ARM64 with MUSL copy": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_darwin_arm64.tar.gz",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.darwin-arm64-musl.tar.gz",
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.darwin-arm64.tar.gz",
+		},
+	},
+	"OSS: Windows ARM64 with MUSL copy": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_windows_arm64.tar.gz",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.windows-arm64-musl.tar.gz",
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.windows-arm64.tar.gz",
+		},
+	},
+	"OSS: FreeBSD AMD64": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_freebsd_amd64.tar.gz",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.freebsd-amd64.tar.gz",
+		},
+	},
+	"OSS: FreeBSD ARM64 SHA256": {
+		input: "gs://bucket/tag/grafana_v1.2.3_102_freebsd_arm64.tar.gz.sha256",
+		output: []string{
+			"artifacts/downloads/v1.2.3/oss/release/grafana-1.2.3.freebsd-arm64.tar.gz.sha256",
+		},
+	},
 	"OSS: Linux AMD64 with MUSL copy": {
 		input: "gs://bucket/tag/grafana_v1.2.3_102_linux_amd64.tar.gz",
 		output: []string{
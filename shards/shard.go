@@ -0,0 +1,71 @@
+package shards
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Shard is one of Total parts of a test suite. Index starts at 1, so that a shard can be written as '1/4' like jest's '--shard'.
+type Shard struct {
+	Index int
+	Total int
+}
+
+// All is the only shard of a test suite that isn't split.
+var All = Shard{Index: 1, Total: 1}
+
+var ErrorInvalidShard = errors.New("shards must be written as '<index>/<total>', like '1/4', with 1 <= index <= total")
+
+// Parse parses a shard like '1/4'. An empty string is All.
+func Parse(s string) (Shard, error) {
+	if s == "" {
+		return All, nil
+	}
+
+	index, total, ok := strings.Cut(s, "/")
+	if !ok {
+		return Shard{}, fmt.Errorf("%s: %w", s, ErrorInvalidShard)
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return Shard{}, fmt.Errorf("%s: %w", s, ErrorInvalidShard)
+	}
+	t, err := strconv.Atoi(total)
+	if err != nil {
+		return Shard{}, fmt.Errorf("%s: %w", s, ErrorInvalidShard)
+	}
+	if i < 1 || t < 1 || i > t {
+		return Shard{}, fmt.Errorf("%s: %w", s, ErrorInvalidShard)
+	}
+
+	return Shard{Index: i, Total: t}, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// Name is used in file and folder names, like 'shard-1-of-4'.
+func (s Shard) Name() string {
+	return fmt.Sprintf("shard-%d-of-%d", s.Index, s.Total)
+}
+
+// Select returns the items of the shard. Items are dealt to the shards in turn, so the items must be in the same order in every shard.
+func Select[T any](items []T, s Shard) []T {
+	selected := []T{}
+	for i, v := range items {
+		if i%s.Total == s.Index-1 {
+			selected = append(selected, v)
+		}
+	}
+
+	return selected
+}
+
+// SelectCommand returns a shell command that prints the lines of its input that are in the shard, like Select does for a slice.
+func SelectCommand(s Shard) string {
+	return fmt.Sprintf("awk '(NR - 1) %% %d == %d'", s.Total, s.Index-1)
+}
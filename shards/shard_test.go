@@ -0,0 +1,68 @@
+package shards_test
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-build/shards"
+)
+
+func TestParse(t *testing.T) {
+	valid := map[string]shards.Shard{
+		"":    shards.All,
+		"1/1": {Index: 1, Total: 1},
+		"2/4": {Index: 2, Total: 4},
+	}
+	for s, expect := range valid {
+		shard, err := shards.Parse(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if shard != expect {
+			t.Errorf("%s: expected %+v but got %+v", s, expect, shard)
+		}
+	}
+
+	invalid := []string{"1", "0/4", "5/4", "a/4", "1/b", "-1/2"}
+	for _, s := range invalid {
+		if _, err := shards.Parse(s); !errors.Is(err, shards.ErrorInvalidShard) {
+			t.Errorf("%s: expected ErrorInvalidShard but got '%v'", s, err)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	cases := map[shards.Shard][]string{
+		shards.All:           items,
+		{Index: 1, Total: 2}: {"a", "c", "e"},
+		{Index: 2, Total: 2}: {"b", "d"},
+		{Index: 3, Total: 6}: {"c"},
+		{Index: 6, Total: 6}: {},
+	}
+
+	for shard, expect := range cases {
+		if s := shards.Select(items, shard); !reflect.DeepEqual(s, expect) {
+			t.Errorf("%s: expected %v but got %v", shard, expect, s)
+		}
+	}
+}
+
+func TestSelectCommand(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	for _, shard := range []shards.Shard{shards.All, {Index: 1, Total: 2}, {Index: 2, Total: 2}, {Index: 3, Total: 6}, {Index: 6, Total: 6}} {
+		cmd := exec.Command("/bin/sh", "-c", shards.SelectCommand(shard))
+		cmd.Stdin = strings.NewReader(strings.Join(items, "\n") + "\n")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s, expect := strings.Fields(string(out)), shards.Select(items, shard); strings.Join(s, ",") != strings.Join(expect, ",") {
+			t.Errorf("%s: expected the command to select %v like Select but got %v", shard, expect, s)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package stringutil
+
+import "fmt"
+
+// FormatSize returns a size in bytes in binary units, like '1.5 KiB'. Negative sizes, like the change of a size, keep their sign.
+func FormatSize(b int64) string {
+	const unit = 1024
+	sign := ""
+	if b < 0 {
+		sign, b = "-", -b
+	}
+	if b < unit {
+		return fmt.Sprintf("%s%d B", sign, b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%s%.1f %ciB", sign, float64(b)/float64(div), "KMGTPE"[exp])
+}
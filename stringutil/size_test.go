@@ -0,0 +1,23 @@
+package stringutil_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-build/stringutil"
+)
+
+func TestFormatSize(t *testing.T) {
+	for b, expect := range map[int64]string{
+		0:         "0 B",
+		1000:      "1000 B",
+		-1000:     "-1000 B",
+		1536:      "1.5 KiB",
+		-1536:     "-1.5 KiB",
+		5 << 20:   "5.0 MiB",
+		3<<30 + 1: "3.0 GiB",
+	} {
+		if s := stringutil.FormatSize(b); s != expect {
+			t.Errorf("expected %d to be '%s' but got '%s'", b, expect, s)
+		}
+	}
+}